@@ -0,0 +1,150 @@
+package panics
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen is returned by Try if the breaker of the action is open for its alias.
+var ErrBreakerOpen = errors.New("panics: breaker is open")
+
+// BreakerState is the state of a circuit of Breaker.
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // calls are allowed, panics are counted
+	BreakerOpen                         // calls are rejected until the cooldown elapses
+	BreakerHalfOpen                     // a probe call is allowed, its result decides whether the circuit is closed or opened again
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig configures Breaker.
+type BreakerConfig struct {
+	Threshold int           // the circuit is opened if more than Threshold panics are recovered within Window
+	Window    time.Duration // the sliding window to count panics
+	Cooldown  time.Duration // how long the circuit stays open before a probe call is allowed
+
+	OnStateChange func(alias string, from, to BreakerState) // called on state transitions, after the lock of breaker is released
+	Now           func() time.Time                          // the clock, time.Now if nil
+}
+
+// Breaker is a circuit breaker keyed by alias, which is opened if the calls with an alias panic repeatedly. Use it with
+// action.WithBreaker so the panics are tracked through the recovery, and Go/Try calls are short-circuited while it's open.
+type Breaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    BreakerState
+	panics   panicWindow
+	openedAt time.Time
+	probing  bool // a probe call is running in half-open state
+}
+
+// NewBreaker create a breaker with the config.
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Breaker{cfg: cfg, circuits: make(map[string]*circuit)}
+}
+
+// Allow reports whether a call with the alias is allowed. In half-open state, only one probe call is allowed until its
+// result is recorded.
+func (b *Breaker) Allow(alias string) bool {
+	var notify func()
+	defer func() { notifyTransition(notify) }() // runs after unlocking, so the callback can call methods of breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(alias)
+	switch c.state {
+	case BreakerOpen:
+		if b.cfg.Now().Sub(c.openedAt) < b.cfg.Cooldown {
+			return false
+		}
+		notify = b.transit(alias, c, BreakerHalfOpen)
+		c.probing = true
+		return true
+	case BreakerHalfOpen:
+		if c.probing {
+			return false
+		}
+		c.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// State return the current state of the circuit of the alias.
+func (b *Breaker) State(alias string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.circuit(alias).state
+}
+
+// record the result of a call with the alias.
+func (b *Breaker) record(alias string, panicked bool) {
+	var notify func()
+	defer func() { notifyTransition(notify) }()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(alias)
+	switch {
+	case c.state == BreakerHalfOpen && panicked:
+		c.probing, c.openedAt = false, b.cfg.Now()
+		notify = b.transit(alias, c, BreakerOpen)
+	case c.state == BreakerHalfOpen:
+		c.probing, c.panics = false, panicWindow{}
+		notify = b.transit(alias, c, BreakerClosed)
+	case c.state == BreakerClosed && panicked:
+		if c.panics.add(b.cfg.Now(), b.cfg.Window) > b.cfg.Threshold {
+			c.openedAt = b.cfg.Now()
+			notify = b.transit(alias, c, BreakerOpen)
+		}
+	}
+}
+
+func (b *Breaker) circuit(alias string) *circuit {
+	c, ok := b.circuits[alias]
+	if !ok {
+		c = &circuit{}
+		b.circuits[alias] = c
+	}
+	return c
+}
+
+// transit change the state of circuit, the returned function calls OnStateChange and must be called without the lock.
+func (b *Breaker) transit(alias string, c *circuit, to BreakerState) func() {
+	from := c.state
+	c.state = to
+	if b.cfg.OnStateChange == nil {
+		return nil
+	}
+	return func() { b.cfg.OnStateChange(alias, from, to) }
+}
+
+func notifyTransition(notify func()) {
+	if notify != nil {
+		notify()
+	}
+}
@@ -0,0 +1,72 @@
+package panics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	var (
+		now         = time.Unix(0, 0)
+		transitions []string
+	)
+	var b *Breaker
+	b = NewBreaker(BreakerConfig{
+		Threshold: 1,
+		Window:    time.Minute,
+		Cooldown:  10 * time.Second,
+		OnStateChange: func(alias string, from, to BreakerState) {
+			transitions = append(transitions, fmt.Sprintf("%s:%s->%s", alias, from, to))
+			assert.Equal(t, to, b.State(alias), "the callback can call methods of breaker")
+		},
+		Now: func() time.Time { return now },
+	})
+	a := Use(Default()).WithBreaker(b)
+	ran := 0
+	try := func(alias string, panicked bool) error {
+		return a.Alias(alias).Try(func() {
+			ran++
+			if panicked {
+				panicFunc("a")
+			}
+		})
+	}
+
+	assert.IsType(t, &PanicError{}, try("a", true))
+	assert.Equal(t, BreakerClosed, b.State("a"))
+	now = now.Add(time.Second)
+	assert.IsType(t, &PanicError{}, try("a", true))
+	assert.Equal(t, BreakerOpen, b.State("a"))
+	assert.Equal(t, BreakerClosed, b.State("b"), "circuits are keyed by alias")
+	assert.NoError(t, try("b", false))
+
+	ran = 0
+	assert.ErrorIs(t, try("a", false), ErrBreakerOpen)
+	assert.Equal(t, 0, ran, "f is short-circuited while open")
+
+	// the probe panics, so the circuit is opened again
+	now = now.Add(10 * time.Second)
+	assert.True(t, b.Allow("a"))
+	assert.False(t, b.Allow("a"), "only one probe is allowed in half-open state")
+	func() { defer a.Alias("a").Recover(); panicFunc("a") }()
+	assert.Equal(t, BreakerOpen, b.State("a"))
+
+	// the probe succeeds, so the circuit is closed
+	now = now.Add(10 * time.Second)
+	assert.NoError(t, try("a", false))
+	assert.Equal(t, 1, ran)
+	assert.Equal(t, BreakerClosed, b.State("a"))
+	assert.IsType(t, &PanicError{}, try("a", true), "panics before the circuit was closed are forgotten")
+	assert.Equal(t, BreakerClosed, b.State("a"))
+
+	assert.Equal(t, []string{
+		"a:closed->open",
+		"a:open->half-open",
+		"a:half-open->open",
+		"a:open->half-open",
+		"a:half-open->closed",
+	}, transitions)
+}
@@ -0,0 +1,93 @@
+package panics
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	buildInfoOnce sync.Once
+	buildInfo     atomic.Pointer[BuildInfo]
+)
+
+// BuildInfo is the build and VCS metadata of the running binary, read once from debug.ReadBuildInfo and attached to
+// every PanicInfo.
+type BuildInfo struct {
+	Service   string            `json:"service,omitempty"`    // main module path, or the name given to SetService
+	Version   string            `json:"version,omitempty"`    // main module version, or the version given to SetService
+	Path      string            `json:"path,omitempty"`       // main package path
+	GoVersion string            `json:"go_version,omitempty"` // go version that built the binary
+	Revision  string            `json:"revision,omitempty"`   // vcs.revision
+	Time      string            `json:"time,omitempty"`       // vcs.time
+	Modified  bool              `json:"modified,omitempty"`   // vcs.modified, the working tree was dirty when built
+	Settings  map[string]string `json:"settings,omitempty"`   // all build settings, like -tags, GOOS and GOARCH
+}
+
+// String return a short description like "service@version (revision, dirty)".
+func (b *BuildInfo) String() string {
+	if b == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(b.Service)
+	if b.Version != "" {
+		sb.WriteString("@")
+		sb.WriteString(b.Version)
+	}
+	if b.Revision != "" {
+		sb.WriteString(" (")
+		sb.WriteString(b.Revision)
+		if b.Modified {
+			sb.WriteString(", dirty")
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// Build return the build info attached to PanicInfo.
+func Build() *BuildInfo {
+	buildInfoOnce.Do(func() { buildInfo.Store(readBuildInfo()) })
+	return buildInfo.Load()
+}
+
+// SetService overwrite the service name and version of the build info, empty values are ignored.
+func SetService(name, version string) {
+	b := *Build()
+	if name != "" {
+		b.Service = name
+	}
+	if version != "" {
+		b.Version = version
+	}
+	buildInfo.Store(&b)
+}
+
+func readBuildInfo() *BuildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return &BuildInfo{GoVersion: runtime.Version()}
+	}
+	b := &BuildInfo{
+		Service:   info.Main.Path,
+		Version:   info.Main.Version,
+		Path:      info.Path,
+		GoVersion: info.GoVersion,
+		Settings:  make(map[string]string, len(info.Settings)),
+	}
+	for _, setting := range info.Settings {
+		b.Settings[setting.Key] = setting.Value
+		switch setting.Key {
+		case "vcs.revision":
+			b.Revision = setting.Value
+		case "vcs.time":
+			b.Time = setting.Value
+		case "vcs.modified":
+			b.Modified = setting.Value == "true"
+		}
+	}
+	return b
+}
@@ -0,0 +1,42 @@
+package panics
+
+import (
+	"bytes"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInfo(t *testing.T) {
+	origin := Build()
+	defer buildInfo.Store(origin)
+
+	assert.Equal(t, runtime.Version(), origin.GoVersion)
+
+	SetService("orders", "v1.2.3")
+	assert.Equal(t, "orders", Build().Service)
+	assert.Equal(t, "v1.2.3", Build().Version)
+	assert.Equal(t, origin.GoVersion, Build().GoVersion)
+
+	var info PanicInfo
+	func() {
+		defer Use(Default().SetWatch(func(pi PanicInfo) { info = pi })).Recover()
+		panic("a")
+	}()
+	assert.Equal(t, Build(), info.Build)
+
+	var buf bytes.Buffer
+	originLogger := logger
+	defer func() { logger = originLogger }()
+	logger = log.New(&buf, "", 0)
+	SimpleLog(info)
+	assert.True(t, strings.Contains(buf.String(), "Build:orders@v1.2.3"))
+}
+
+func TestBuildInfoString(t *testing.T) {
+	assert.Equal(t, "", (*BuildInfo)(nil).String())
+	assert.Equal(t, "svc@v1 (abc, dirty)", (&BuildInfo{Service: "svc", Version: "v1", Revision: "abc", Modified: true}).String())
+}
@@ -0,0 +1,155 @@
+package panics
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"runtime/debug"
+	"strings"
+)
+
+// FrameChecker adapt a function on parsed frames to a checker which can be used with SetIgnorePositionChecker, the
+// frame is ignored if f returns true.
+func FrameChecker(f func(frame Position) bool) ignorePositionChecker {
+	var parser action
+	return func(funcLine, fileLine string) bool { return f(parser.parseLocation(funcLine, fileLine)) }
+}
+
+// IgnorePackages return a checker which ignores frames of functions in the given packages, sub-packages are not included.
+func IgnorePackages(pkgs ...string) ignorePositionChecker {
+	return FrameChecker(func(frame Position) bool {
+		for _, pkg := range pkgs {
+			if frame.Package == pkg {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IgnoreModules return a checker which ignores frames of functions in the given modules, including all their packages.
+func IgnoreModules(modules ...string) ignorePositionChecker {
+	return FrameChecker(func(frame Position) bool {
+		for _, module := range modules {
+			if hasPathPrefix(frame.Package, module) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IgnoreFunctions return a checker which ignores frames of functions whose full name matches one of the glob patterns,
+// the syntax of patterns is the same as path.Match, e.g. `github.com/acme/lib.(*Client).*`.
+func IgnoreFunctions(patterns ...string) ignorePositionChecker {
+	return FrameChecker(func(frame Position) bool {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, frame.Function); matched {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IgnoreFileRegexp return a checker which ignores frames whose file path matches one of the patterns.
+func IgnoreFileRegexp(patterns ...*regexp.Regexp) ignorePositionChecker {
+	return FrameChecker(func(frame Position) bool {
+		for _, pattern := range patterns {
+			if pattern.MatchString(frame.File) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IgnoreVendor return a checker which ignores frames of files in vendor directories.
+func IgnoreVendor() ignorePositionChecker {
+	return FrameChecker(func(frame Position) bool { return strings.Contains(frame.File, "/vendor/") })
+}
+
+// And return a checker which ignores a frame only if all the checkers ignore it.
+func And(checkers ...ignorePositionChecker) ignorePositionChecker {
+	return func(funcLine, fileLine string) bool {
+		for _, check := range checkers {
+			if !check(funcLine, fileLine) {
+				return false
+			}
+		}
+		return len(checkers) > 0
+	}
+}
+
+// Or return a checker which ignores a frame if any of the checkers ignores it.
+func Or(checkers ...ignorePositionChecker) ignorePositionChecker {
+	return func(funcLine, fileLine string) bool {
+		for _, check := range checkers {
+			if check(funcLine, fileLine) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not return a checker which ignores a frame only if the given checker doesn't ignore it.
+func Not(checker ignorePositionChecker) ignorePositionChecker {
+	return func(funcLine, fileLine string) bool { return !checker(funcLine, fileLine) }
+}
+
+// OwnedModulesChecker return a checker which ignores every frame that doesn't belong to the owned modules, so frames of
+// standard libraries and third-party modules (from GOROOT, the module cache or vendor directories) are all skipped when
+// finding Actual. The main module read from build info is owned if no module prefix is given, and package main is
+// always owned. Ownership is decided by the package path of the function instead of the file path, so it works no
+// matter where GOROOT and the module cache are, and with binaries built with -trimpath.
+func OwnedModulesChecker(modules ...string) ignorePositionChecker {
+	if len(modules) == 0 {
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Path != "" {
+			modules = []string{info.Main.Path}
+		}
+	}
+	return func(funcLine, fileLine string) bool {
+		pkg := functionPackage(functionName(funcLine))
+		if pkg == "main" {
+			return false
+		}
+		for _, module := range modules {
+			if hasPathPrefix(pkg, module) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// functionName return the function name of a function line in the stack, e.g. `main.(*T).foo` for
+// `main.(*T).foo({0x1, 0x2})`.
+func functionName(funcLine string) string {
+	if strings.HasSuffix(funcLine, ")") {
+		if idx := strings.LastIndex(funcLine, "("); idx > 0 {
+			return funcLine[:idx]
+		}
+	}
+	return funcLine
+}
+
+// functionPackage return the package path of a function name, e.g. `github.com/a/b` for `github.com/a/b.(*T).foo`.
+// The dots in the last element of the path are escaped by the linker, e.g. `gopkg.in/yaml%2ev3.Marshal`, they're
+// unescaped so the import path is returned.
+func functionPackage(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	pkg := function
+	if idx := strings.Index(function[lastSlash+1:], "."); idx >= 0 {
+		pkg = function[:lastSlash+1+idx]
+	}
+	if last, err := url.PathUnescape(pkg[lastSlash+1:]); err == nil {
+		pkg = pkg[:lastSlash+1] + last
+	}
+	return pkg
+}
+
+// hasPathPrefix reports whether path is prefix or in the sub-tree of prefix.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix) && (strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/')
+}
@@ -0,0 +1,106 @@
+package panics
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnedModulesChecker(t *testing.T) {
+	t.Run("Lines", func(t *testing.T) {
+		check := OwnedModulesChecker("github.com/acme/app")
+		assert.False(t, check("github.com/acme/app/internal/x.(*T).Do({0x1, 0x2})", "\t/home/me/src/net/x.go:1"))
+		assert.False(t, check("github.com/acme/app.Run()", "\t/a/app.go:1"))
+		assert.False(t, check("main.main()", "\t/a/main.go:1"))
+		assert.True(t, check("github.com/acme/app-lib.Run()", "\t/go/pkg/mod/github.com/acme/app-lib@v1.0.0/lib.go:1"))
+		assert.True(t, check("net/http.(*conn).serve(0xc000)", "\t/usr/local/go/src/net/http/server.go:1"))
+		assert.True(t, check("fmt.Fprint({0x0, 0x0}, {0x1, 0x1, 0x1})", "\t/usr/local/go/src/fmt/print.go:1"))
+
+		check = OwnedModulesChecker("gopkg.in/foo.v1")
+		assert.False(t, check("gopkg.in/foo%2ev1.Boom(...)", "\t/a/foo.go:1"), "dots escaped by the linker are unescaped")
+	})
+	t.Run("MainModule", func(t *testing.T) {
+		var info PanicInfo
+		a := Use(Default().SetWatch(func(pi PanicInfo) { info = pi }).SetIgnorePositionChecker(OwnedModulesChecker()))
+		func() {
+			defer a.Recover()
+			fmt.Fprint(nil, 1)
+		}()
+		assert.Equal(t, "fmt.Fprint", info.Direct.Function)
+		assert.Equal(t, panicsPkg+".TestOwnedModulesChecker.func2.2", info.Actual.Function)
+	})
+}
+
+func TestFunctionName(t *testing.T) {
+	assert.Equal(t, "main.(*T).foo", functionName("main.(*T).foo({0x1, 0x2})"))
+	assert.Equal(t, "main.F[...]", functionName("main.F[...](...)"))
+	assert.Equal(t, "github.com/a/b", functionPackage("github.com/a/b.(*T).foo"))
+	assert.Equal(t, "main", functionPackage("main.main.func1"))
+	assert.Equal(t, "gopkg.in/yaml.v3", functionPackage("gopkg.in/yaml%2ev3.Marshal"))
+	assert.Equal(t, "example.com/lib.v2", functionPackage("example.com/lib%2ev2.(*T).Boom.func1"))
+}
+
+func TestDeclarativeCheckers(t *testing.T) {
+	const (
+		clientLine = "github.com/acme/lib/client.(*Client).Do(0xc000)"
+		clientFile = "\t/go/pkg/mod/github.com/acme/lib@v1.0.0/client/client.go:10 +0x20"
+		vendorLine = "github.com/acme/dep.Run()"
+		vendorFile = "\t/src/app/vendor/github.com/acme/dep/run.go:3 +0x20"
+		appLine    = "github.com/acme/app.Serve()"
+		appFile    = "\t/src/app/serve.go:8 +0x20"
+	)
+	for name, c := range map[string]struct {
+		checker                ignorePositionChecker
+		client, vendor, appLoc bool
+	}{
+		"Packages":      {IgnorePackages("github.com/acme/lib"), false, false, false},
+		"SubPackages":   {IgnorePackages("github.com/acme/lib/client"), true, false, false},
+		"Modules":       {IgnoreModules("github.com/acme/lib", "github.com/acme/dep"), true, true, false},
+		"Functions":     {IgnoreFunctions("github.com/acme/lib/client.(*Client).*"), true, false, false},
+		"FileRegexp":    {IgnoreFileRegexp(regexp.MustCompile(`/pkg/mod/`)), true, false, false},
+		"Vendor":        {IgnoreVendor(), false, true, false},
+		"Or":            {Or(IgnoreVendor(), IgnoreModules("github.com/acme/lib")), true, true, false},
+		"And":           {And(IgnoreModules("github.com/acme"), Not(IgnoreVendor())), true, false, true},
+		"EmptyAnd":      {And(), false, false, false},
+		"Not":           {Not(IgnoreModules("github.com/acme/app")), true, true, false},
+		"FrameFunction": {FrameChecker(func(frame Position) bool { return frame.Line == 8 }), false, false, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.client, c.checker(clientLine, clientFile))
+			assert.Equal(t, c.vendor, c.checker(vendorLine, vendorFile))
+			assert.Equal(t, c.appLoc, c.checker(appLine, appFile))
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	var info PanicInfo
+	a := Use(Default().SetWatch(func(pi PanicInfo) { info = pi }).SetExplain(true))
+	func() {
+		defer a.Ignore(IgnoreFunctions(panicsPkg + ".TestExplain.func2.1")).Recover()
+		func() { fmt.Fprint(nil, 1) }()
+	}()
+
+	assert.Len(t, info.Explain, 4)
+	assert.Equal(t, "fmt.Fprint", info.Explain[0].Frame.Function)
+	assert.Equal(t, "selected as direct: the frame called panic", info.Explain[0].Reason)
+
+	assert.True(t, info.Explain[1].Ignored)
+	assert.Equal(t, "fmt.Fprint", info.Explain[1].Frame.Function)
+	assert.Contains(t, info.Explain[1].Reason, "settings checker #0")
+
+	assert.True(t, info.Explain[2].Ignored)
+	assert.Equal(t, panicsPkg+".TestExplain.func2.1", info.Explain[2].Frame.Function)
+	assert.Contains(t, info.Explain[2].Reason, "action checker #0")
+
+	assert.True(t, info.Explain[3].Selected)
+	assert.Equal(t, info.Actual, info.Explain[3].Frame)
+
+	func() {
+		defer Use(Default().SetWatch(func(pi PanicInfo) { info = pi })).Recover()
+		panic("a")
+	}()
+	assert.Nil(t, info.Explain)
+}
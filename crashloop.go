@@ -0,0 +1,84 @@
+package panics
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CrashLoopGuard terminates the process if there are too many panics in a short time, for crash-only services which
+// would rather restart than limp along.
+type CrashLoopGuard struct {
+	Threshold int           // the process exits if more than Threshold panics are recovered within Window
+	Window    time.Duration // the sliding window to count panics
+	PerAlias  bool          // count panics of every alias separately
+	ExitCode  int           // the exit code
+
+	BeforeExit func()           // runs after the before-exit hook of settings, e.g. to flush asynchronous sinks
+	Exit       func(code int)   // terminate the process, os.Exit if nil
+	Now        func() time.Time // the clock, time.Now if nil
+}
+
+type crashLoop struct {
+	cfg CrashLoopGuard
+
+	mu     sync.Mutex
+	panics map[string]*panicWindow // panics in the window by alias
+}
+
+// panicWindow is the time of panics in a sliding window.
+type panicWindow struct{ times []time.Time }
+
+// add a panic happened at now, the count of panics in the window is returned.
+func (w *panicWindow) add(now time.Time, window time.Duration) int {
+	// drop the panics out of the window
+	i := 0
+	for i < len(w.times) && now.Sub(w.times[i]) >= window {
+		i++
+	}
+	w.times = append(w.times[i:], now)
+	return len(w.times)
+}
+
+func newCrashLoop(cfg CrashLoopGuard) *crashLoop {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &crashLoop{cfg: cfg, panics: make(map[string]*panicWindow)}
+}
+
+// record a panic recovered with alias, it reports whether the threshold is exceeded.
+func (c *crashLoop) record(alias string) bool {
+	if !c.cfg.PerAlias {
+		alias = ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w, ok := c.panics[alias]
+	if !ok {
+		w = &panicWindow{}
+		c.panics[alias] = w
+	}
+	return w.add(c.cfg.Now(), c.cfg.Window) > c.cfg.Threshold
+}
+
+// guardCrashLoop terminate the process if the crash-loop guard of settings is tripped by this panic.
+func (a action) guardCrashLoop(ctx context.Context, safe bool) {
+	s := a.a.load()
+	if s.crashLoop == nil || !s.crashLoop.record(a.alias) {
+		return
+	}
+	for _, f := range []func(){s.beforeExit, s.crashLoop.cfg.BeforeExit} {
+		if f != nil && safe {
+			fallbackSafeRun(ctx, &f)
+		} else if f != nil {
+			f()
+		}
+	}
+	if s.crashLoop.cfg.Exit != nil {
+		s.crashLoop.cfg.Exit(s.crashLoop.cfg.ExitCode)
+	} else {
+		exit(s.crashLoop.cfg.ExitCode)
+	}
+}
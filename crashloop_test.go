@@ -0,0 +1,62 @@
+package panics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrashLoopGuard(t *testing.T) {
+	var (
+		now      = time.Unix(0, 0)
+		exitCode = -1
+		hooks    []string
+	)
+	newAction := func(perAlias bool) action {
+		exitCode, hooks = -1, nil
+		return Use(Default().SetBeforeExit(func() { hooks = append(hooks, "settings") }).SetCrashLoopGuard(&CrashLoopGuard{
+			Threshold:  2,
+			Window:     time.Minute,
+			PerAlias:   perAlias,
+			ExitCode:   5,
+			BeforeExit: func() { hooks = append(hooks, "guard") },
+			Exit:       func(code int) { exitCode = code },
+			Now:        func() time.Time { return now },
+		}))
+	}
+	panicWith := func(a action, alias string) {
+		defer a.Alias(alias).Recover()
+		panic("a")
+	}
+
+	t.Run("Window", func(t *testing.T) {
+		a := newAction(false)
+		panicWith(a, "a")
+		now = now.Add(30 * time.Second)
+		panicWith(a, "b")
+		now = now.Add(31 * time.Second)
+		panicWith(a, "a")
+		assert.Equal(t, -1, exitCode, "the first panic is out of the window")
+		panicWith(a, "b")
+		assert.Equal(t, 5, exitCode)
+		assert.Equal(t, []string{"settings", "guard"}, hooks)
+	})
+	t.Run("PerAlias", func(t *testing.T) {
+		a := newAction(true)
+		panicWith(a, "a")
+		panicWith(a, "b")
+		panicWith(a, "a")
+		panicWith(a, "b")
+		assert.Equal(t, -1, exitCode)
+		panicWith(a, "a")
+		assert.Equal(t, 5, exitCode)
+	})
+	t.Run("Succeed", func(t *testing.T) {
+		a := newAction(false)
+		for i := 0; i < 5; i++ {
+			func() { defer a.Recover() }()
+		}
+		assert.Equal(t, -1, exitCode)
+	})
+}
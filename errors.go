@@ -0,0 +1,25 @@
+package panics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrGoexit is the error of a function run by helpers like Supervisor, which exited by runtime.Goexit (e.g. t.FailNow)
+// instead of returning.
+var ErrGoexit = errors.New("panics: goroutine exited by runtime.Goexit")
+
+// PanicError is the error of a recovered panic, which is returned by helpers like Try.
+type PanicError struct {
+	Value any       // the object which is got by recover()
+	Stack string    // the stack dumps for the panic
+	Info  PanicInfo // the analyzed information of the panic, the innermost one if there are nested panics
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// Unwrap return the recovered value if it's an error, so errors.Is/As work with the value.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
@@ -0,0 +1,62 @@
+package panics
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RunEveryOptions configures RunEvery.
+type RunEveryOptions struct {
+	Jitter               float64 // the fraction of every interval which is randomized, in [0, 1]
+	Immediate            bool    // run f once at start instead of waiting for the first interval
+	MaxConsecutivePanics int     // stop after f panics so many times in a row, never stop if 0
+}
+
+// RunEvery run f every interval until ctx is done, the panics of f are recovered and it keeps running. A tick is
+// skipped if the last run of f hasn't returned yet. nil is returned once ctx is done and the running f returns, or the
+// *PanicError of the last run if f panics MaxConsecutivePanics times in a row.
+func (a action) RunEvery(ctx context.Context, interval time.Duration, f func(ctx context.Context) error, opts RunEveryOptions) error {
+	next := func() time.Duration {
+		d := float64(interval)
+		if opts.Jitter > 0 {
+			d -= d * min(opts.Jitter, 1) * rand.Float64()
+		}
+		return time.Duration(d)
+	}
+	first := next()
+	if opts.Immediate {
+		first = 0
+	}
+	timer := time.NewTimer(first)
+	defer timer.Stop()
+
+	var (
+		done        = make(chan error, 1)
+		running     = false
+		consecutive = 0
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			if running {
+				<-done
+			}
+			return nil
+		case err := <-done:
+			running = false
+			if _, panicked := err.(*PanicError); !panicked {
+				consecutive = 0
+			} else if consecutive++; opts.MaxConsecutivePanics > 0 && consecutive >= opts.MaxConsecutivePanics {
+				return err
+			}
+		case <-timer.C:
+			timer.Reset(next())
+			if running {
+				continue
+			}
+			running = true
+			go a.runTask(ctx, func() error { return f(ctx) }, func(err error) { done <- err })
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunEvery(t *testing.T) {
+	var watched atomic.Int64
+	a := Use(Default().SetWatch(func(info PanicInfo) { watched.Add(1) }))
+
+	t.Run("MaxConsecutivePanics", func(t *testing.T) {
+		watched.Store(0)
+		var calls atomic.Int64
+		err := a.RunEvery(context.Background(), time.Millisecond, func(ctx context.Context) error {
+			switch calls.Add(1) {
+			case 1, 3, 4:
+				panicFunc("a")
+			case 2:
+				return errors.New("a")
+			}
+			return nil
+		}, RunEveryOptions{Jitter: 0.5, MaxConsecutivePanics: 2})
+		var panicErr *PanicError
+		if assert.ErrorAs(t, err, &panicErr) {
+			assert.Equal(t, "a", panicErr.Value)
+		}
+		assert.EqualValues(t, 4, calls.Load(), "the panic count is reset by the run without panic")
+		assert.EqualValues(t, 3, watched.Load())
+	})
+	t.Run("Immediate", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		start := time.Now()
+		err := a.RunEvery(ctx, time.Hour, func(ctx context.Context) error { cancel(); return nil }, RunEveryOptions{Immediate: true})
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Minute)
+	})
+	t.Run("Goexit", func(t *testing.T) {
+		var calls atomic.Int64
+		ctx, cancel := context.WithCancel(context.Background())
+		err := a.RunEvery(ctx, time.Millisecond, func(ctx context.Context) error {
+			if calls.Add(1) == 1 {
+				runtime.Goexit()
+			}
+			cancel()
+			return nil
+		}, RunEveryOptions{})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, calls.Load(), int64(2), "the runner keeps going after runtime.Goexit")
+	})
+	t.Run("SkipOverlapping", func(t *testing.T) {
+		var (
+			calls   atomic.Int64
+			release = make(chan struct{})
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+			close(release)
+		}()
+		err := a.RunEvery(ctx, time.Millisecond, func(ctx context.Context) error {
+			calls.Add(1)
+			<-release
+			return nil
+		}, RunEveryOptions{})
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+}
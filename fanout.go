@@ -0,0 +1,93 @@
+package panics
+
+import (
+	"context"
+	"sync"
+)
+
+// FanOutOption configures ForEach and Map.
+type FanOutOption func(*fanOut)
+
+type fanOut struct {
+	a        action
+	failFast bool
+}
+
+// FailFast cancel the context passed to the remaining items once an item panics or returns an error, the items not
+// started yet are skipped with the error of the context. All items run by default.
+func FailFast() FanOutOption { return func(o *fanOut) { o.failFast = true } }
+
+// RecoverWith recover the panics of items with the action instead of the one with global settings.
+func RecoverWith(a action) FanOutOption { return func(o *fanOut) { o.a = a } }
+
+// ForEach run f for every item with at most limit items in parallel (no limit if limit is less than 1). The panic of
+// every item is recovered with the item index in PanicInfo.Attrs as "index". The error of every item is returned by
+// index, a *PanicError if it panicked or ErrGoexit if it called runtime.Goexit, errors.Join can be used to check if any
+// item failed.
+func ForEach[T any](ctx context.Context, items []T, limit int, f func(ctx context.Context, item T) error, opts ...FanOutOption) []error {
+	_, errs := Map(ctx, items, limit, func(ctx context.Context, item T) (struct{}, error) {
+		return struct{}{}, f(ctx, item)
+	}, opts...)
+	return errs
+}
+
+// Map run f for every item the same way as ForEach, and return the results and errors by index. The result is the
+// zero value if the item panicked. If the action re-panics (Repanic, rules or pass-through values), the first re-panicked
+// value is re-panicked on the calling goroutine after all items finish.
+func Map[T, R any](ctx context.Context, items []T, limit int, f func(ctx context.Context, item T) (R, error), opts ...FanOutOption) ([]R, []error) {
+	o := fanOut{a: globalSettings.s.newAction()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if limit < 1 || limit > len(items) {
+		limit = len(items)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results = make([]R, len(items))
+		errs    = make([]error, len(items))
+		sem     = make(chan struct{}, limit)
+		wg      sync.WaitGroup
+
+		repanicOnce sync.Once
+		repanicked  any // the first panic re-panicked by the action
+	)
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				// the panic re-panicked by the action is passed to the caller of Map instead of crashing the process
+				if v := recover(); v != nil {
+					repanicOnce.Do(func() { repanicked = v })
+				}
+				<-sem
+				wg.Done()
+			}()
+			o.a.With("index", i).runTask(ctx, func() (err error) {
+				results[i], err = f(ctx, item)
+				return err
+			}, func(err error) {
+				if errs[i] = err; err != nil && o.failFast {
+					cancel()
+				}
+			})
+		}()
+	}
+	wg.Wait()
+	if repanicked != nil {
+		panic(repanicked)
+	}
+	return results, errs
+}
@@ -0,0 +1,87 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		indexes []any
+	)
+	a := Use(Default().SetWatch(func(info PanicInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		indexes = append(indexes, info.AttrsMap()["index"])
+	}))
+	errA := errors.New("a")
+	items := []int{0, 1, 2, 3, 4, 5}
+	f := func(ctx context.Context, item int) (string, error) {
+		switch item {
+		case 2:
+			panicFunc("a")
+		case 4:
+			return "", errA
+		}
+		return strconv.Itoa(item), nil
+	}
+
+	results, errs := Map(context.Background(), items, 2, f, RecoverWith(a))
+	assert.Equal(t, []string{"0", "1", "", "3", "", "5"}, results)
+	assert.Len(t, errs, len(items))
+	var panicErr *PanicError
+	if assert.ErrorAs(t, errs[2], &panicErr) {
+		assert.Equal(t, "a", panicErr.Value)
+		assert.EqualValues(t, 2, panicErr.Info.AttrsMap()["index"])
+	}
+	assert.ErrorIs(t, errs[4], errA)
+	for _, i := range []int{0, 1, 3, 5} {
+		assert.NoError(t, errs[i])
+	}
+	assert.Equal(t, []any{int64(2)}, indexes)
+
+	errs = ForEach(context.Background(), items, 1, func(ctx context.Context, item int) error {
+		_, err := f(ctx, item)
+		return err
+	}, RecoverWith(a), FailFast())
+	assert.Equal(t, []error{nil, nil, errs[2], context.Canceled, context.Canceled, context.Canceled}, errs)
+	assert.ErrorAs(t, errs[2], &panicErr)
+}
+
+func TestForEachGoexit(t *testing.T) {
+	errs := ForEach(context.Background(), []int{0, 1}, 0, func(ctx context.Context, item int) error {
+		if item == 0 {
+			runtime.Goexit()
+		}
+		return nil
+	})
+	assert.Equal(t, []error{ErrGoexit, nil}, errs)
+}
+
+func TestMapRepanic(t *testing.T) {
+	var errs []error
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		errs = ForEach(context.Background(), []int{0, 1}, 0, func(ctx context.Context, item int) error {
+			if item == 0 {
+				panic(http.ErrAbortHandler)
+			}
+			return nil
+		})
+	}, "pass-through values are re-panicked on the calling goroutine")
+	assert.Nil(t, errs)
+
+	assert.PanicsWithValue(t, "a", func() {
+		Map(context.Background(), []int{0}, 0, func(ctx context.Context, item int) (int, error) {
+			panicFunc("a")
+			return 0, nil
+		}, RecoverWith(Use(Default()).Repanic()))
+	})
+}
@@ -0,0 +1,89 @@
+package panics
+
+import (
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultGoroutineDumpSize     = 1 << 20
+	defaultGoroutineDumpInterval = time.Minute
+)
+
+var (
+	goroutineHeaderPattern = regexp.MustCompile(`^goroutine (\d+) .*\[(.*)\]:$`)
+
+	// used by actions which call CaptureGoroutines(true) while their settings have no GoroutineDump
+	defaultGoroutineDumper = newGoroutineDumper(GoroutineDump{})
+)
+
+// GoroutineDump configures capturing the stacks of all goroutines when a panic is recovered. Capturing stops the world,
+// so it should only be enabled for specific panics and is rate limited.
+type GoroutineDump struct {
+	When        func(PanicInfo) bool // decide whether stacks should be captured for the panic, nil means all panics
+	MaxBytes    int                  // size cap of the captured stacks, 1MB if not positive
+	MinInterval time.Duration        // minimum interval between two captures, 1 minute if not positive
+}
+
+// Goroutine is a goroutine parsed from the stacks of all goroutines.
+type Goroutine struct {
+	ID     int64      // goroutine id
+	State  string     // the text in brackets of the header line, like "running" or "chan receive, 2 minutes"
+	Stack  string     // the raw text of this goroutine, including the header line
+	Frames []Position // the frames from the innermost to the outermost, the last one may be the "created by" frame
+}
+
+type goroutineDumper struct {
+	cfg  GoroutineDump
+	last atomic.Int64 // unix nano of the last capture
+}
+
+func newGoroutineDumper(cfg GoroutineDump) *goroutineDumper {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultGoroutineDumpSize
+	}
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = defaultGoroutineDumpInterval
+	}
+	return &goroutineDumper{cfg: cfg}
+}
+
+// dump capture stacks of all goroutines, nil is returned if the last capture is too close.
+func (d *goroutineDumper) dump(a *action) []Goroutine {
+	now, last := time.Now().UnixNano(), d.last.Load()
+	if last != 0 && now-last < int64(d.cfg.MinInterval) {
+		return nil
+	}
+	if !d.last.CompareAndSwap(last, now) {
+		// another panic is capturing
+		return nil
+	}
+	buf := make([]byte, d.cfg.MaxBytes)
+	return a.parseGoroutines(string(buf[:runtime.Stack(buf, true)]))
+}
+
+func (a *action) parseGoroutines(stacks string) []Goroutine {
+	var goroutines []Goroutine
+	for _, block := range strings.Split(stacks, "\n\n") {
+		lines := strings.Split(strings.TrimRight(block, "\n"), "\n")
+		match := goroutineHeaderPattern.FindStringSubmatch(lines[0])
+		if match == nil {
+			continue
+		}
+		id, _ := strconv.ParseInt(match[1], 10, 64)
+		g := Goroutine{ID: id, State: match[2], Stack: block}
+		// the stack may be truncated by the size cap or have elided frames, so only complete function/file line pairs are parsed
+		for i := 1; i < len(lines); i++ {
+			if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+				g.Frames = append(g.Frames, a.parseLocation(lines[i], lines[i+1]))
+				i++
+			}
+		}
+		goroutines = append(goroutines, g)
+	}
+	return goroutines
+}
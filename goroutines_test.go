@@ -0,0 +1,73 @@
+package panics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutineDump(t *testing.T) {
+	blocked := make(chan struct{})
+	defer close(blocked)
+	go func() { <-blocked }()
+	time.Sleep(10 * time.Millisecond)
+
+	t.Run("When", func(t *testing.T) {
+		var infos []PanicInfo
+		a := Use(Default().SetWatch(func(pi PanicInfo) { infos = append(infos, pi) }).
+			SetGoroutineDump(&GoroutineDump{When: func(pi PanicInfo) bool { return pi.Alias == "dump" }, MinInterval: time.Hour}))
+		for _, alias := range []string{"skip", "dump", "dump"} {
+			func() {
+				defer a.Alias(alias).Recover()
+				panic("a")
+			}()
+		}
+		assert.Empty(t, infos[0].Goroutines)
+		assert.True(t, len(infos[1].Goroutines) >= 2)
+		assert.Equal(t, "running", infos[1].Goroutines[0].State)
+		var recovering, blocking bool
+		for _, frame := range infos[1].Goroutines[0].Frames {
+			recovering = recovering || strings.HasPrefix(frame.Function, panicsPkg+".TestGoroutineDump")
+		}
+		for _, g := range infos[1].Goroutines {
+			blocking = blocking || g.State == "chan receive"
+		}
+		assert.True(t, recovering)
+		assert.True(t, blocking)
+		assert.Empty(t, infos[2].Goroutines, "rate limited")
+	})
+	t.Run("Action", func(t *testing.T) {
+		var info PanicInfo
+		a := Use(Default().SetWatch(func(pi PanicInfo) { info = pi }).SetGoroutineDump(&GoroutineDump{}))
+		func() {
+			defer a.CaptureGoroutines(false).Recover()
+			panic("a")
+		}()
+		assert.Empty(t, info.Goroutines)
+
+		defaultGoroutineDumper.last.Store(0)
+		a = Use(Default().SetWatch(func(pi PanicInfo) { info = pi }))
+		func() {
+			defer a.CaptureGoroutines(true).Recover()
+			panic("a")
+		}()
+		assert.NotEmpty(t, info.Goroutines)
+	})
+}
+
+func TestParseGoroutines(t *testing.T) {
+	stacks := "goroutine 1 [running]:\nmain.main()\n\t/a/main.go:12 +0x74\n\n" +
+		"goroutine 7 [chan receive, 2 minutes]:\nmain.worker(0x1)\n\t/a/main.go:20 +0x10\n...additional frames elided...\n" +
+		"created by main.main in goroutine 1\n\t/a/main.go:10 +0x20\n\n" +
+		"goroutine 8 [select]:\nmain.wor"
+	goroutines := (&action{}).parseGoroutines(stacks)
+	assert.Len(t, goroutines, 3)
+	assert.Equal(t, int64(7), goroutines[1].ID)
+	assert.Equal(t, "chan receive, 2 minutes", goroutines[1].State)
+	assert.Len(t, goroutines[1].Frames, 2)
+	assert.Equal(t, "main.worker", goroutines[1].Frames[0].Function)
+	assert.Equal(t, int64(10), goroutines[1].Frames[1].Line)
+	assert.Empty(t, goroutines[2].Frames)
+}
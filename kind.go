@@ -0,0 +1,71 @@
+package panics
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Kind is the classification of a recovered panic.
+type Kind string
+
+const (
+	KindNilDereference  Kind = "nil_dereference"    // invalid memory address or nil pointer dereference
+	KindIndexOutOfRange Kind = "index_out_of_range" // index or slice bounds out of range
+	KindNilMapWrite     Kind = "nil_map_write"      // assignment to entry in nil map
+	KindTypeAssertion   Kind = "type_assertion"     // failed type assertion, recovered as *runtime.TypeAssertionError
+	KindClosedChannel   Kind = "closed_channel"     // send on closed channel, or close of closed channel
+	KindDivideByZero    Kind = "divide_by_zero"     // integer divide by zero
+	KindRuntime         Kind = "runtime"            // other runtime errors
+	KindNil             Kind = "nil"                // panic(nil), recovered as *runtime.PanicNilError
+	KindString          Kind = "string"             // panic with a string
+	KindError           Kind = "error"              // panic with an error which is not a runtime error
+	KindOther           Kind = "other"              // panic with other values
+)
+
+// runtimeErrorKinds map the message patterns of runtime errors to kinds.
+var runtimeErrorKinds = []struct {
+	pattern string
+	kind    Kind
+}{
+	{"nil pointer dereference", KindNilDereference},
+	{"out of range", KindIndexOutOfRange},
+	{"assignment to entry in nil map", KindNilMapWrite},
+	{"closed channel", KindClosedChannel},
+	{"divide by zero", KindDivideByZero},
+}
+
+// kindOf classify the recovered value, a *PanicError re-panicked by RepanicWrapped is classified by its value.
+func kindOf(v any) Kind {
+	switch v := v.(type) {
+	case *PanicError:
+		return kindOf(v.Value)
+	case string:
+		return KindString
+	case runtime.Error:
+		var (
+			nilErr       *runtime.PanicNilError
+			assertionErr *runtime.TypeAssertionError
+		)
+		switch {
+		case errors.As(v, &nilErr):
+			return KindNil
+		case errors.As(v, &assertionErr):
+			return KindTypeAssertion
+		case fmt.Sprintf("%T", v) == "runtime.boundsError":
+			return KindIndexOutOfRange
+		}
+		msg := v.Error()
+		for _, k := range runtimeErrorKinds {
+			if strings.Contains(msg, k.pattern) {
+				return k.kind
+			}
+		}
+		return KindRuntime
+	case error:
+		return KindError
+	default:
+		return KindOther
+	}
+}
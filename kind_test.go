@@ -0,0 +1,134 @@
+package panics
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilPanic(t *testing.T) {
+	t.Run("PanicNilError", func(t *testing.T) {
+		var (
+			info    PanicInfo
+			succeed bool
+		)
+		func() {
+			defer Use(Default().SetWatch(func(pi PanicInfo) { info = pi })).Succeed(func() { succeed = true }).Recover()
+			panic(nil)
+		}()
+		assert.False(t, succeed)
+		assert.IsType(t, &runtime.PanicNilError{}, info.Error)
+		assert.Equal(t, KindNil, info.Kind)
+	})
+	t.Run("PanicNilCompatible", func(t *testing.T) {
+		if os.Getenv("PANICS_TEST_PANICNIL") != "" {
+			testDetectNilPanic(t)
+			return
+		}
+		cmd := exec.Command(os.Args[0], "-test.run=^TestNilPanic$/^PanicNilCompatible$", "-test.v")
+		cmd.Env = append(os.Environ(), "PANICS_TEST_PANICNIL=1", "GODEBUG=panicnil=1")
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+		assert.True(t, strings.Contains(string(out), "--- PASS"), string(out))
+	})
+}
+
+func testDetectNilPanic(t *testing.T) {
+	var (
+		info    PanicInfo
+		succeed bool
+	)
+	func() {
+		defer Use(Default().SetWatch(func(pi PanicInfo) { info = pi })).Succeed(func() { succeed = true }).Recover()
+		panic(nil)
+	}()
+	assert.True(t, succeed, "recover() returns nil with panicnil=1")
+
+	succeed = false
+	a := Use(Default().SetWatch(func(pi PanicInfo) { info = pi }).SetDetectNilPanic(true))
+	func() {
+		defer a.Succeed(func() { succeed = true }).Recover()
+		panic(nil)
+	}()
+	assert.False(t, succeed)
+	assert.IsType(t, &runtime.PanicNilError{}, info.Error)
+	assert.Equal(t, KindNil, info.Kind)
+	assert.Equal(t, panicsPkg+".testDetectNilPanic.func3", info.Actual.Function)
+
+	info = PanicInfo{}
+	SetDetectNilPanic(true)
+	SetWatch(func(pi PanicInfo) { info = pi })
+	defer func() { SetDetectNilPanic(false); SetWatch(discard) }()
+	func() {
+		defer Recover()
+		panic(nil)
+	}()
+	assert.Equal(t, KindNil, info.Kind)
+
+	var goexit bool
+	err := Use(Default()).Goexit(func() { goexit = true }).Try(func() { panic(nil) })
+	assert.False(t, goexit)
+	assert.IsType(t, &runtime.PanicNilError{}, err.(*PanicError).Value)
+
+	// a recover which is not run by the panic directly
+	succeed = false
+	func() {
+		defer func() {
+			func() {
+				defer a.Succeed(func() { succeed = true }).Recover()
+			}()
+			recover()
+		}()
+		panic(nil)
+	}()
+	assert.True(t, succeed)
+}
+
+func TestKind(t *testing.T) {
+	var (
+		nilPtr   *struct{ a int }
+		nilMap   map[string]int
+		closedCh = make(chan int)
+		zero     = 0
+		slice    = []int{}
+		value    any
+	)
+	close(closedCh)
+	for kind, f := range map[Kind]func(){
+		KindNilDereference:  func() { _ = nilPtr.a },
+		KindIndexOutOfRange: func() { _ = slice[zero] },
+		KindNilMapWrite:     func() { nilMap["a"] = 1 },
+		KindTypeAssertion:   func() { _ = value.(int) },
+		KindClosedChannel:   func() { closedCh <- 1 },
+		KindDivideByZero:    func() { _ = 1 / zero },
+		KindNil:             func() { panic(nil) },
+		KindString:          func() { panic("a") },
+		KindError:           func() { panic(errors.New("a")) },
+		KindOther:           func() { panic(1) },
+	} {
+		t.Run(string(kind), func(t *testing.T) {
+			err := Use(Default()).Try(f)
+			assert.Equal(t, kind, err.(*PanicError).Info.Kind)
+		})
+	}
+	assert.Equal(t, KindRuntime, kindOf(runtimeError("unknown")))
+
+	a := Use(Default())
+	err := a.Try(func() {
+		defer a.RepanicWrapped().Recover()
+		var m map[string]int
+		m["a"] = 1
+	})
+	assert.IsType(t, &PanicError{}, err.(*PanicError).Value)
+	assert.Equal(t, KindNilMapWrite, err.(*PanicError).Info.Kind, "the value of wrapped panic is classified")
+}
+
+type runtimeError string
+
+func (e runtimeError) Error() string { return string(e) }
+func (e runtimeError) RuntimeError() {}
@@ -2,6 +2,11 @@ package panics
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -12,6 +17,18 @@ const (
 )
 
 var (
+	pkgPath      = reflect.TypeOf(action{}).PkgPath()
+	nilPanicArgs = regexp.MustCompile(`^panic\(\{0x0\??, 0x0\??\}\)$`)
+	// the functions which call recover() and are deferred by users
+	recoverFunctions = map[string]bool{
+		pkgPath + ".action.Recover":            true,
+		pkgPath + ".action.RecoverWithContext": true,
+		pkgPath + ".action.run.func1":          true,
+	}
+
+	// explain mode is enabled for all settings if environment variable PANICS_DEBUG is set to a true value
+	explainFromEnv, _ = strconv.ParseBool(os.Getenv("PANICS_DEBUG"))
+
 	unknownLoc = Position{FileLine: "UNKNOWN", FuncLine: "UNKNOWN:-1", File: "UNKNOWN", Function: "UNKNOWN", Depth: -1}
 )
 
@@ -21,51 +38,194 @@ type action struct {
 	safe      *bool
 	alias     string
 	extra     any
+	attrs     []slog.Attr
+	goroutine *bool
+	ignores   []ignorePositionChecker
+	watch     func(PanicInfo)
+	addWatch  bool
 	always    *func()
 	onPanic   *func(PanicInfo)
 	onSucceed *func()
+	onGoexit  *func()
+	repanic   *RepanicPolicy
+	breaker   *Breaker
+	attempt   int
 }
 
+// RepanicPolicy decides whether a recovered panic is propagated after it's reported.
+type RepanicPolicy int
+
+const (
+	RepanicNone         RepanicPolicy = iota // the panic is swallowed, it's the default policy
+	RepanicOriginal                          // re-panic with the original value after the watch functions and hooks run
+	RepanicWrappedError                      // re-panic with a *PanicError which keeps the original value and stack
+)
+
 // Recover recover panics.
 func (a action) Recover() {
 	panicErr := recover()
-	a.postRecover(context.Background(), panicErr)
+	a.postRecover(context.Background(), panicErr, false)
 }
 
 // RecoverWithContext recover panic with context, the context can be get from PanicInfo.Context.
 func (a action) RecoverWithContext(ctx context.Context) {
 	panicErr := recover()
-	a.postRecover(ctx, panicErr)
+	a.postRecover(ctx, panicErr, false)
 }
 
-func (a action) postRecover(ctx context.Context, panicErr any) {
+// Go run f in a new goroutine and recover panics from it. f is skipped if the breaker of this action is open.
+func (a action) Go(f func()) { go a.run(context.Background(), f) }
+
+// Try run f and recover panics from it. A *PanicError is returned if a panic recovered. Unlike a deferred Recover, Try
+// knows whether f completed, so if f calls runtime.Goexit (e.g. t.FailNow), the Goexit hook runs instead of Succeed.
+// ErrBreakerOpen is returned without running f if the breaker of this action is open.
+func (a action) Try(f func()) error { return a.run(context.Background(), f) }
+
+func (a action) run(ctx context.Context, f func()) (err error) {
+	if a.breaker != nil && !a.breaker.Allow(a.alias) {
+		return ErrBreakerOpen
+	}
+	completed := false
+	defer func() {
+		panicErr := recover()
+		goexit := false
+		if panicErr == nil && !completed {
+			// recover() returns nil if the goroutine is exiting by runtime.Goexit, or panic(nil) with GODEBUG=panicnil=1
+			if isNilPanicking() {
+				panicErr = new(runtime.PanicNilError)
+			} else {
+				goexit = true
+			}
+		}
+		if infos := a.postRecover(ctx, panicErr, goexit); len(infos) > 0 {
+			err = &PanicError{Value: panicErr, Stack: infos[0].Stack, Info: infos[0]}
+		}
+	}()
+	f()
+	completed = true
+	return nil
+}
+
+// runTask run f with recovery like run, and call done with the error of f, a *PanicError if it panicked or ErrGoexit
+// if it called runtime.Goexit. done is called by defer, so it's called even if the goroutine is exiting. If the panic
+// is re-panicked by this action (Repanic, rules or pass-through values), done is called with a *PanicError of it before
+// the panic goes on.
+func (a action) runTask(ctx context.Context, f func() error, done func(err error)) {
+	var (
+		err       error
+		completed bool
+	)
+	defer func() {
+		if completed {
+			done(err)
+			return
+		}
+		v := recover()
+		if v == nil {
+			done(ErrGoexit)
+			return
+		}
+		panicErr, ok := v.(*PanicError)
+		if !ok {
+			panicErr = &PanicError{Value: v}
+		}
+		done(panicErr)
+		panic(v)
+	}()
+	if runErr := a.run(ctx, func() { err = f() }); runErr != nil {
+		err = runErr
+	}
+	completed = true
+}
+
+// postRecover handle the recovered value, the PanicInfo passed to watch functions are returned. If goexit is true, the
+// goroutine is exiting by runtime.Goexit.
+func (a action) postRecover(ctx context.Context, panicErr any, goexit bool) []PanicInfo {
 	safe := a.needRunFallbackSafe()
+	var infos []PanicInfo
+	if panicErr == nil && !goexit && a.a.load().detectNilPanic && isNilPanicking() {
+		// recover() returns nil for panic(nil) with GODEBUG=panicnil=1, report it the same way as the default behavior
+		panicErr = new(runtime.PanicNilError)
+	}
+	if panicErr != nil && a.passThrough(panicErr) {
+		if a.a.load().passThroughAlways {
+			if safe {
+				fallbackSafeRun(ctx, a.always)
+			} else if a.always != nil && *a.always != nil {
+				(*a.always)()
+			}
+		}
+		a.recordBreaker(false)
+		panic(panicErr)
+	}
+	a.recordBreaker(panicErr != nil)
 	if panicErr != nil {
 		buf := make([]byte, panicBufSize)
 		buf = buf[:runtime.Stack(buf, false)]
 		stackStr := string(buf[:runtime.Stack(buf, false)])
 		locs := a.findPanics(stackStr, panicErr)
+		var chain []PanicLink
+		if a.a.load().chain {
+			locs, chain = chainPanics(locs, panicErr)
+		}
+		fields := a.contextFields(ctx)
+		attrs := a.mergedAttrs()
+		var (
+			goroutines []Goroutine
+			dumped     bool
+		)
 		for _, loc := range locs {
 			info := PanicInfo{
 				Direct:  loc.Direct,
 				Actual:  loc.Actual,
 				Error:   panicErr,
+				Kind:    kindOf(panicErr),
 				Stack:   stackStr,
 				Alias:   a.alias,
 				Context: ctx,
 				Extra:   a.extra,
+				Fields:  fields,
+				Attrs:   attrs,
+				Build:   Build(),
+				Explain: loc.Explain,
+				Chain:   chain,
+				Attempt: a.attempt,
+			}
+			if !dumped {
+				goroutines, dumped = a.dumpGoroutines(info)
+			}
+			info.Goroutines = goroutines
+			if r := a.a.load().sourceReader; r != nil {
+				info.DirectSource, info.ActualSource = r.snippet(info.Direct), r.snippet(info.Actual)
+			}
+			if r := a.a.load().redactor; r != nil {
+				info = r.Redact(info)
+			}
+			if explainFromEnv {
+				logExplain(info)
 			}
 			if safe {
-				fallbackSafeRunWithInfo(ctx, &a.a.load().watch, info)
+				for _, watch := range a.watches() {
+					fallbackSafeRunWithInfo(ctx, &watch, info)
+				}
 				fallbackSafeRunWithInfo(ctx, a.onPanic, info)
 			} else {
-				if a.a.load().watch != nil {
-					a.a.load().watch(info)
+				for _, watch := range a.watches() {
+					if watch != nil {
+						watch(info)
+					}
 				}
 				if a.onPanic != nil && *a.onPanic != nil {
 					(*a.onPanic)(info)
 				}
 			}
+			infos = append(infos, info)
+		}
+	} else if goexit {
+		if safe {
+			fallbackSafeRun(ctx, a.onGoexit)
+		} else if a.onGoexit != nil && *a.onGoexit != nil {
+			(*a.onGoexit)()
 		}
 	} else if safe {
 		fallbackSafeRun(ctx, a.onSucceed)
@@ -77,8 +237,83 @@ func (a action) postRecover(ctx context.Context, panicErr any) {
 	} else if a.always != nil && *a.always != nil {
 		(*a.always)()
 	}
+	if panicErr != nil {
+		a.guardCrashLoop(ctx, safe)
+		a.applyOutcome(ctx, panicErr, infos, safe)
+	}
+	return infos
+}
+
+// passThrough reports whether the panic must not be swallowed.
+func (a action) passThrough(panicErr any) bool {
+	for _, match := range a.a.load().passThrough {
+		if match(panicErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordBreaker record the result of this recover to the breaker of action.
+func (a action) recordBreaker(panicked bool) {
+	if a.breaker != nil {
+		a.breaker.record(a.alias, panicked)
+	}
+}
+
+func (a action) repanicPolicy() RepanicPolicy {
+	if a.repanic != nil {
+		// repanic setting on action has higher priority
+		return *a.repanic
+	}
+	return a.a.load().repanic
+}
+
+// isNilPanicking reports whether the deferred function of this package which called recover() is run by panic(nil).
+// It must be called from the deferred function which called recover(), or from its direct callee like postRecover, and
+// the deferred function must be listed in recoverFunctions.
+func isNilPanicking() bool {
+	buf := make([]byte, panicBufSize)
+	lines := strings.Split(string(buf[:runtime.Stack(buf, false)]), "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "panic(") {
+			continue
+		}
+		// the frame above `panic(...)` must be the deferred function which called recover(), or a function which is not
+		// run by the panic directly is executing (it's run by a deferred function, or the panic is recovered by others)
+		return i >= 2 && recoverFunctions[strings.TrimSuffix(functionName(lines[i-2]), "-fm")] && nilPanicArgs.MatchString(line)
+	}
+	return false
+}
+
+// chainPanics merge all locations of nested panics to one location, the chain of them is returned too.
+func chainPanics(locs []panicLocation, panicErr any) ([]panicLocation, []PanicLink) {
+	chain := make([]PanicLink, len(locs))
+	merged := panicLocation{Direct: locs[0].Direct, Actual: locs[0].Actual}
+	for i, loc := range locs {
+		chain[i] = PanicLink{Direct: loc.Direct, Actual: loc.Actual, Depth: loc.Actual.Depth}
+		merged.Explain = append(merged.Explain, loc.Explain...)
+	}
+	chain[0].Error = panicErr
+	return []panicLocation{merged}, chain
+}
+
+// watches return the watch functions to be called on panic, the watch of action replaces the one of settings unless
+// it's added by AddWatch.
+func (a action) watches() []func(PanicInfo) {
+	switch {
+	case a.watch == nil:
+		return []func(PanicInfo){a.a.load().watch}
+	case a.addWatch:
+		return []func(PanicInfo){a.a.load().watch, a.watch}
+	default:
+		return []func(PanicInfo){a.watch}
+	}
 }
 
+// explaining reports whether the decisions of finding Actual should be recorded.
+func (a action) explaining() bool { return explainFromEnv || a.a.load().explain }
+
 func (a action) needRunFallbackSafe() bool {
 	if a.safe != nil {
 		// safe setting on action has higher priority
@@ -99,6 +334,14 @@ func (a action) Succeed(f func()) action { a.onSucceed = &f; return a }
 // SucceedRef the given `f` will be executed if no panic recovered. Use `Succeed` if `f` won't change.
 func (a action) SucceedRef(f *func()) action { a.onSucceed = f; return a }
 
+// Goexit the given `f` will be executed if the goroutine is exiting by runtime.Goexit, it only works with Go/Try.
+// Use `GoexitRef` if `f` may change.
+func (a action) Goexit(f func()) action { a.onGoexit = &f; return a }
+
+// GoexitRef the given `f` will be executed if the goroutine is exiting by runtime.Goexit, it only works with Go/Try.
+// Use `Goexit` if `f` won't change.
+func (a action) GoexitRef(f *func()) action { a.onGoexit = f; return a }
+
 // Panic the given `f` will be executed if a panic recovered. Use `PanicRef` if `f` may change.
 func (a action) Panic(f func(PanicInfo)) action { a.onPanic = &f; return a }
 
@@ -111,12 +354,57 @@ func (a action) PanicRef(f *func(PanicInfo)) action { a.onPanic = f; return a }
 // safe as true/false to overwrite the setting.
 func (a action) Safe(safe bool) action { a.safe = &safe; return a }
 
+// Repanic re-panic with the original value after the watch functions and hooks run, so reporting and crash-only
+// semantics can coexist. It has a higher priority than the repanic policy of settings.
+func (a action) Repanic() action { return a.setRepanic(RepanicOriginal) }
+
+// RepanicWrapped re-panic with a *PanicError which keeps the original value and stack after the watch functions and
+// hooks run. It has a higher priority than the repanic policy of settings.
+func (a action) RepanicWrapped() action { return a.setRepanic(RepanicWrappedError) }
+
+// NoRepanic swallow the panic after the watch functions and hooks run, even if the repanic policy or rules of settings
+// would re-panic or exit. It has the same priority as Repanic.
+func (a action) NoRepanic() action { return a.setRepanic(RepanicNone) }
+
+func (a action) setRepanic(p RepanicPolicy) action { a.repanic = &p; return a }
+
+// WithBreaker track panics of this recover with the breaker, keyed by alias. Go/Try are short-circuited while the breaker
+// is open for the alias.
+func (a action) WithBreaker(b *Breaker) action { a.breaker = b; return a }
+
 // Alias set alias for this recover. We can get alias in PanicInfo.Alias so we can known where the panic is recoverd.
 func (a action) Alias(alias string) action { a.alias = alias; return a }
 
 // WithExtra anything you want to get from panic info.
 func (a action) WithExtra(extra any) action { a.extra = extra; return a }
 
+// Ignore append checkers for this recover only, they are used after the checkers of settings when finding Actual.
+func (a action) Ignore(checkers ...ignorePositionChecker) action {
+	a.ignores = append(a.ignores[:len(a.ignores):len(a.ignores)], checkers...)
+	return a
+}
+
+// Watch set a watch function for this recover only, it replaces the watch function of settings.
+func (a action) Watch(f func(PanicInfo)) action { a.watch, a.addWatch = f, false; return a }
+
+// AddWatch set a watch function for this recover only, it's called after the watch function of settings.
+func (a action) AddWatch(f func(PanicInfo)) action { a.watch, a.addWatch = f, true; return a }
+
+// CaptureGoroutines controls whether stacks of all goroutines are captured if a panic recovered, it overwrites the
+// GoroutineDump.When of settings. The capture is still size capped and rate limited.
+func (a action) CaptureGoroutines(capture bool) action { a.goroutine = &capture; return a }
+
+// With append key/value pairs to the attributes of this recover, the pairs are interpreted the same way as slog.Logger.With.
+// Attributes accumulate across calls and can be got from PanicInfo.Attrs.
+func (a action) With(kv ...any) action { return a.WithAttrs(slog.Group("", kv...).Value.Group()...) }
+
+// WithAttrs append attributes to this recover. Attributes accumulate across calls and can be got from PanicInfo.Attrs.
+func (a action) WithAttrs(attrs ...slog.Attr) action {
+	// cap the slice so actions derived from the same builder never share the appended elements
+	a.attrs = append(a.attrs[:len(a.attrs):len(a.attrs)], attrs...)
+	return a
+}
+
 type Position struct {
 	FuncLine string // the raw string of function line in the stack
 	FileLine string // the raw string of file line in the stack
@@ -124,6 +412,7 @@ type Position struct {
 	File     string // file path
 	Line     int64  // line number
 	Function string // function name
+	Package  string // package path of the function
 	/*
 	   we may recover a panic that caused by the logic in another recover function, like the following stack:
 
@@ -144,21 +433,127 @@ type Position struct {
 	Depth int
 }
 
+type panicLocation struct {
+	Direct  Position
+	Actual  Position
+	Explain []Decision
+}
+
+// Decision is a record of how a frame is treated when finding Direct and Actual, it's only recorded in explain mode.
+type Decision struct {
+	Frame    Position // the frame considered, Depth is the depth of the panic it belongs to
+	Ignored  bool     // the frame is ignored by a checker
+	Selected bool     // the frame is selected as Actual
+	Reason   string   // a readable description of the decision, including which checker ignored the frame
+}
+
 type PanicInfo struct {
 	Direct Position // the code position that directly caseud this panic
 	Actual Position // the code position that actually caused this panic
 
 	Stack   string          // the stack dumps for this panic
 	Error   any             // the object which is got by recover()
+	Kind    Kind            // the classification of Error
 	Context context.Context // the argument that pass to RecoverWithContext, or context.Background if called with Recover
 	Alias   string          // the alias of the code position that called Recover/RecoverWithContext
 	Extra   any             // the paramater pass to WithExtra method
+	Fields  map[string]any  // the values extracted from Context by the extractors registered with AddContextField
+	Attrs   []slog.Attr     // the attributes of settings (SetAttrs/SetLabels) followed by the ones passed to With/WithAttrs
+	Build   *BuildInfo      // the build info of the binary, shared by all panics and should not be modified
+
+	Goroutines   []Goroutine    // stacks of all goroutines, only captured if enabled by SetGoroutineDump or CaptureGoroutines
+	DirectSource *SourceSnippet // the source code around Direct, only read if enabled by SetSourceContext
+	ActualSource *SourceSnippet // the source code around Actual, only read if enabled by SetSourceContext
+	Explain      []Decision     // how frames are treated when finding Direct and Actual, only recorded in explain mode
+	Chain        []PanicLink    // all nested panics from the innermost (Depth 0) to the outermost, only set in chain mode
+	Attempt      int            // the attempt number starting from 1 if recovered by Retry, otherwise 0
+}
+
+// PanicLink is a panic of the chain of nested panics. When a recover handler panics while handling a panic, we get a
+// chain of panics from the stack.
+type PanicLink struct {
+	Error  any      // the recovered value, only available for the innermost one (Depth 0) which is got by recover()
+	Direct Position // the code position that directly caused this panic
+	Actual Position // the code position that actually caused this panic
+	Depth  int      // the depth of this panic in the stack
+}
+
+// AttrsMap return PanicInfo.Attrs as a map, groups are converted to nested maps. Later attributes overwrite the earlier
+// ones with the same key, it's helpful for sinks that render JSON.
+func (p PanicInfo) AttrsMap() map[string]any {
+	if len(p.Attrs) == 0 {
+		return nil
+	}
+	return attrsToMap(p.Attrs)
+}
+
+func attrsToMap(attrs []slog.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		v := attr.Value.Resolve()
+		if v.Kind() == slog.KindGroup {
+			m[attr.Key] = attrsToMap(v.Group())
+		} else {
+			m[attr.Key] = v.Any()
+		}
+	}
+	return m
+}
+
+func (a action) contextFields(ctx context.Context) map[string]any {
+	extractors := a.a.load().contextFields
+	if len(extractors) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(extractors))
+	for _, field := range extractors {
+		if v, ok := field.extract(ctx); ok {
+			fields[field.name] = v
+		}
+	}
+	return fields
 }
 
-func (s *action) findPanics(stack string, err any) []struct {
-	Direct Position
-	Actual Position
-} {
+// dumpGoroutines capture stacks of all goroutines if needed, the second result reports whether the decision has been made
+// so it won't be made again for other locations of the same panic.
+func (a action) dumpGoroutines(info PanicInfo) ([]Goroutine, bool) {
+	dumper := a.a.load().goroutineDumper
+	switch {
+	case a.goroutine != nil && !*a.goroutine:
+		return nil, true
+	case a.goroutine != nil && dumper == nil:
+		dumper = defaultGoroutineDumper
+	case a.goroutine == nil && (dumper == nil || dumper.cfg.When != nil && !dumper.cfg.When(info)):
+		return nil, true
+	}
+	return dumper.dump(&a), true
+}
+
+func (a action) mergedAttrs() []slog.Attr {
+	defaults := a.a.load().attrs
+	if len(defaults) == 0 {
+		return a.attrs
+	}
+	// attributes set on action take precedence over the settings ones with the same key
+	merged := make([]slog.Attr, 0, len(defaults)+len(a.attrs))
+	for _, attr := range defaults {
+		if indexAttr(a.attrs, attr.Key) < 0 {
+			merged = append(merged, attr)
+		}
+	}
+	return append(merged, a.attrs...)
+}
+
+func indexAttr(attrs []slog.Attr, key string) int {
+	for i, attr := range attrs {
+		if attr.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *action) findPanics(stack string, err any) []panicLocation {
 	/*
 	   goroutine 1 [running]:r
 	   main.recoverSimple()
@@ -173,85 +568,115 @@ func (s *action) findPanics(stack string, err any) []struct {
 	           /Users/selfenth/Code/go/src/github.com/selfenth/expir/main.go:19 +0x194
 	*/
 	var (
-		i     = 0
-		lines = strings.Split(stack, "\n")
+		i       = 0
+		lines   = strings.Split(stack, "\n")
+		explain = s.explaining()
 	)
-	panicLocs := make([]struct {
-		Direct Position
-		Actual Position
-	}, 0, 1)
+	panicLocs := make([]panicLocation, 0, 1)
 
-	var directLoc *Position
+	var (
+		directLoc *Position
+		decisions []Decision
+	)
 	for i < len(lines) {
 		if directLoc != nil {
 			// 有Direct的，说明目前在查找Select的位置
-			if s.isIgnoreLoc(lines[i-1], lines[i]) {
+			if ignored, by := s.isIgnoreLoc(lines[i-1], lines[i], explain); ignored {
 				// 检测当前行是否是被忽略的，如果被忽略的话，往后跳两行
+				if explain {
+					decisions = append(decisions, Decision{Frame: s.parseLocation(lines[i-1], lines[i]), Ignored: true, Reason: "ignored by " + by})
+				}
 				i += 2
 			} else {
 				// 当前行被选中
-				panicLocs = append(panicLocs, struct {
-					Direct Position
-					Actual Position
-				}{
-					Direct: *directLoc,
-					Actual: s.parseLocation(lines[i-1], lines[i]),
-				})
-				directLoc, i = nil, i+1 // 重置，让后续继续查找panic
+				actual := s.parseLocation(lines[i-1], lines[i])
+				if explain {
+					decisions = append(decisions, Decision{Frame: actual, Selected: true, Reason: "selected as actual: not ignored by any checker"})
+				}
+				panicLocs = append(panicLocs, panicLocation{Direct: *directLoc, Actual: actual, Explain: decisions})
+				directLoc, decisions, i = nil, nil, i+1 // 重置，让后续继续查找panic
 			}
 		} else if strings.HasPrefix(lines[i], "panic(") {
 			directLoc, i = ptrOf(s.parseLocation(lines[i+2], lines[i+3])), i+3 // 跳到下下个文件行，开始位置查找
+			if explain {
+				decisions = append(decisions, Decision{Frame: *directLoc, Reason: "selected as direct: the frame called panic"})
+			}
 		} else {
 			i += 1 // 跳到下一个方法行
 		}
 	}
 	if directLoc != nil {
 		// 找到了direct的，没能找到select的，使用direct的兜底
-		panicLocs = append(panicLocs, struct {
-			Direct Position
-			Actual Position
-		}{
-			Direct: *directLoc,
-			Actual: *directLoc,
-		})
+		if explain {
+			decisions = append(decisions, Decision{Frame: *directLoc, Selected: true, Reason: "selected as actual: all frames after direct are ignored, fall back to direct"})
+		}
+		panicLocs = append(panicLocs, panicLocation{Direct: *directLoc, Actual: *directLoc, Explain: decisions})
 	} else if len(panicLocs) == 0 {
 		// 整个堆栈扫下来没有找到panics，理论上不应该存在，这里用特殊内容兜下
-		return []struct {
-			Direct Position
-			Actual Position
-		}{{Direct: unknownLoc, Actual: unknownLoc}}
+		loc := panicLocation{Direct: unknownLoc, Actual: unknownLoc}
+		if explain {
+			loc.Explain = []Decision{{Frame: unknownLoc, Selected: true, Reason: "no panic frame found in the stack"}}
+		}
+		return []panicLocation{loc}
 	}
 	for i := range panicLocs {
 		panicLocs[i].Direct.Depth, panicLocs[i].Actual.Depth = i, i
+		for j := range panicLocs[i].Explain {
+			panicLocs[i].Explain[j].Frame.Depth = i
+		}
 	}
 	return panicLocs
 }
-func (s *action) isIgnoreLoc(funcLine, fileLine string) bool {
-	for _, check := range s.a.load().ignorePositionCheckers {
-		if check(funcLine, fileLine) {
-			return true
+
+// isIgnoreLoc reports whether the frame should be ignored. If describe is true, the checker which ignored the frame is
+// described in the second result.
+func (s *action) isIgnoreLoc(funcLine, fileLine string, describe bool) (bool, string) {
+	for _, checkers := range []struct {
+		source   string
+		checkers []ignorePositionChecker
+	}{{"settings", s.a.load().ignorePositionCheckers}, {"action", s.ignores}} {
+		for idx, check := range checkers.checkers {
+			if check(funcLine, fileLine) {
+				if describe {
+					return true, fmt.Sprintf("%s checker #%d (%s)", checkers.source, idx, checkerName(check))
+				}
+				return true, ""
+			}
 		}
 	}
-	return false
+	return false, ""
 }
 func (s *action) parseLocation(funcLine, fileLine string) Position {
-	funcName := funcLine
-	if idx := strings.Index(funcLine, "("); idx > 0 {
-		funcName = funcLine[:idx]
-	}
+	funcName := functionName(funcLine)
+	pkg := functionPackage(funcName)
 	parts := strings.SplitN(strings.TrimSpace(fileLine), ":", 2)
 	if len(parts) == 1 {
-		return Position{Function: funcName, File: parts[0], Line: -1, FuncLine: funcLine, FileLine: strings.TrimSpace(fileLine)}
+		return Position{Function: funcName, Package: pkg, File: parts[0], Line: -1, FuncLine: funcLine, FileLine: strings.TrimSpace(fileLine)}
 	}
 	line, err := strconv.ParseInt(strings.Split(parts[1], " ")[0], 10, 64)
 	if err != nil {
 		line = -1
 	}
-	return Position{Function: funcName, File: parts[0], Line: line, FuncLine: funcLine, FileLine: strings.TrimSpace(fileLine)}
+	return Position{Function: funcName, Package: pkg, File: parts[0], Line: line, FuncLine: funcLine, FileLine: strings.TrimSpace(fileLine)}
 }
 
 func ptrOf[T any](v T) *T { return &v }
 
+func logExplain(info PanicInfo) {
+	var b strings.Builder
+	for _, decision := range info.Explain {
+		fmt.Fprintf(&b, "\n\t%s %s: %s", decision.Frame.Function, decision.Frame.FileLine, decision.Reason)
+	}
+	logger.Printf("[EXPLAIN]panic(%d) with error:%v.%s\n", info.Actual.Depth, info.Error, b.String())
+}
+
+func checkerName(check ignorePositionChecker) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(check).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "UNKNOWN"
+}
+
 func fallbackSafeRun(ctx context.Context, f *func()) {
 	if f == nil {
 		return
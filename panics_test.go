@@ -2,7 +2,11 @@ package panics
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -292,3 +296,227 @@ func TestNestedPanicsCanBeAnalyzed(t *testing.T) {
 	assert.Equal(t, 1, infos[1].Actual.Depth)
 	assert.Equal(t, 1, infos[1].Direct.Depth)
 }
+
+func TestContextFields(t *testing.T) {
+	type ctxKey struct{}
+	var info PanicInfo
+	a := Use(Default().SetWatch(func(pi PanicInfo) { info = pi }).
+		AddContextField("request_id", func(ctx context.Context) (any, bool) {
+			v, ok := ctx.Value(ctxKey{}).(string)
+			return v, ok
+		}).
+		AddContextField("trace_id", func(ctx context.Context) (any, bool) { return nil, false }))
+
+	func() {
+		defer a.RecoverWithContext(context.WithValue(context.Background(), ctxKey{}, "req-1"))
+		panic("a")
+	}()
+	assert.Equal(t, map[string]any{"request_id": "req-1"}, info.Fields)
+}
+
+func TestAttrs(t *testing.T) {
+	var info PanicInfo
+	base := Use(Default().SetWatch(func(pi PanicInfo) { info = pi }).SetAttrs(slog.String("component", "api"))).
+		With("user", "u1")
+
+	func() {
+		defer base.With("step", 1).WithAttrs(slog.Group("req", slog.String("path", "/a"))).Recover()
+		panic("a")
+	}()
+	assert.Equal(t, []slog.Attr{
+		slog.String("component", "api"),
+		slog.String("user", "u1"),
+		slog.Int("step", 1),
+		slog.Group("req", slog.String("path", "/a")),
+	}, info.Attrs)
+	assert.Equal(t, map[string]any{
+		"component": "api",
+		"user":      "u1",
+		"step":      int64(1),
+		"req":       map[string]any{"path": "/a"},
+	}, info.AttrsMap())
+
+	func() {
+		defer base.With("step", 2).Recover()
+		panic("a")
+	}()
+	assert.Len(t, info.Attrs, 3)
+	assert.Equal(t, slog.Int("step", 2), info.Attrs[2])
+}
+
+func TestLabels(t *testing.T) {
+	var info PanicInfo
+	StoreSettings("TestLabels", Default().SetWatch(func(pi PanicInfo) { info = pi }).
+		SetAttrs(slog.String("team", "infra")).
+		SetLabels("component", "api", "version", "v1", "team", "core"))
+
+	func() {
+		defer ByName("TestLabels").With("version", "v2").Recover()
+		panic("a")
+	}()
+	assert.Equal(t, []slog.Attr{
+		slog.String("team", "core"),
+		slog.String("component", "api"),
+		slog.String("version", "v2"),
+	}, info.Attrs)
+}
+
+func TestActionIgnoreAndWatch(t *testing.T) {
+	var settingsInfo, actionInfo PanicInfo
+	a := Use(Default().SetWatch(func(pi PanicInfo) { settingsInfo = pi }))
+
+	t.Run("Ignore", func(t *testing.T) {
+		func() {
+			defer a.Ignore(IgnoreFunctions(panicsPkg + ".TestActionIgnoreAndWatch.func2.1.1")).Recover()
+			func() { fmt.Fprint(nil, 1) }()
+		}()
+		assert.Equal(t, panicsPkg+".TestActionIgnoreAndWatch.func2.1", settingsInfo.Actual.Function)
+	})
+	t.Run("Watch", func(t *testing.T) {
+		settingsInfo, actionInfo = PanicInfo{}, PanicInfo{}
+		func() {
+			defer a.Watch(func(pi PanicInfo) { actionInfo = pi }).Recover()
+			panic("a")
+		}()
+		assert.Nil(t, settingsInfo.Error)
+		assert.Equal(t, "a", actionInfo.Error)
+	})
+	t.Run("AddWatch", func(t *testing.T) {
+		settingsInfo, actionInfo = PanicInfo{}, PanicInfo{}
+		func() {
+			defer a.AddWatch(func(pi PanicInfo) { actionInfo = pi }).Safe(true).Recover()
+			panic("a")
+		}()
+		assert.Equal(t, "a", settingsInfo.Error)
+		assert.Equal(t, "a", actionInfo.Error)
+	})
+}
+
+func TestChain(t *testing.T) {
+	var infos []PanicInfo
+	a := Use(Default().SetWatch(func(pi PanicInfo) { infos = append(infos, pi) }).SetChain(true))
+	func() {
+		defer a.Alias("1").Recover()
+
+		defer Alias("2").Panic(func(pi PanicInfo) { panic("b") }).Recover()
+		fmt.Fprint(nil, 1)
+	}()
+	assert.Len(t, infos, 1)
+	assert.Equal(t, "b", infos[0].Error)
+	assert.Len(t, infos[0].Chain, 2)
+	assert.Equal(t, "b", infos[0].Chain[0].Error)
+	assert.Equal(t, 0, infos[0].Chain[0].Depth)
+	assert.Equal(t, infos[0].Actual, infos[0].Chain[0].Actual)
+	assert.Nil(t, infos[0].Chain[1].Error)
+	assert.Equal(t, 1, infos[0].Chain[1].Depth)
+	assert.Equal(t, "fmt.Fprint", infos[0].Chain[1].Direct.Function)
+	assert.Equal(t, panicsPkg+".TestChain.func2", infos[0].Chain[1].Actual.Function)
+}
+
+func TestGoAndTry(t *testing.T) {
+	t.Run("Goexit", func(t *testing.T) {
+		var succeed, goexit, always bool
+		done := make(chan struct{})
+		Goexit(func() { goexit = true }).Succeed(func() { succeed = true }).Always(func() { always = true; close(done) }).
+			Go(runtime.Goexit)
+		<-done
+		assert.False(t, succeed)
+		assert.True(t, goexit)
+		assert.True(t, always)
+	})
+	t.Run("Succeed", func(t *testing.T) {
+		var succeed, goexit bool
+		err := Goexit(func() { goexit = true }).Succeed(func() { succeed = true }).Try(func() {})
+		assert.NoError(t, err)
+		assert.True(t, succeed)
+		assert.False(t, goexit)
+	})
+	t.Run("Panic", func(t *testing.T) {
+		var info PanicInfo
+		sentinel := errors.New("sentinel")
+		err := Panic(func(pi PanicInfo) { info = pi }).Try(func() { panic(sentinel) })
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+		assert.ErrorIs(t, err, sentinel)
+		assert.Equal(t, "panic: sentinel", err.Error())
+		assert.Equal(t, info, panicErr.Info)
+		assert.Equal(t, info.Stack, panicErr.Stack)
+		assert.Equal(t, panicsPkg+".TestGoAndTry.func3.2", info.Actual.Function)
+	})
+}
+
+func TestRepanic(t *testing.T) {
+	t.Run("Action", func(t *testing.T) {
+		var (
+			watched          PanicInfo
+			always, panicked bool
+		)
+		a := Use(Default().SetWatch(func(pi PanicInfo) { watched = pi }))
+		assert.PanicsWithValue(t, "a", func() {
+			defer a.Always(func() { always = true }).Panic(func(PanicInfo) { panicked = true }).Repanic().Recover()
+			panicFunc("a")
+		})
+		assert.Equal(t, "a", watched.Error)
+		assert.True(t, always)
+		assert.True(t, panicked)
+	})
+	t.Run("Wrapped", func(t *testing.T) {
+		defer func() {
+			panicErr, ok := recover().(*PanicError)
+			assert.True(t, ok)
+			assert.Equal(t, "a", panicErr.Value)
+			assert.Equal(t, panicsPkg+".panicFunc", panicErr.Info.Actual.Function)
+			assert.True(t, strings.Contains(panicErr.Stack, "panicFunc"))
+		}()
+		defer RepanicWrapped().Recover()
+		panicFunc("a")
+	})
+	t.Run("Settings", func(t *testing.T) {
+		a := Use(Default().SetRepanic(RepanicOriginal))
+		assert.Panics(t, func() {
+			defer a.Recover()
+			panicFunc("a")
+		})
+		assert.NotPanics(t, func() {
+			defer a.Repanic().NoRepanic().Recover()
+			panicFunc("a")
+		})
+		assert.NotPanics(t, func() {
+			defer a.Recover()
+		})
+	})
+}
+
+func panicFunc(v any) { panic(v) }
+
+func TestPassThrough(t *testing.T) {
+	var (
+		watched                   bool
+		always, panicked, succeed bool
+	)
+	a := Use(Default().SetWatch(func(pi PanicInfo) { watched = true }))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		defer a.Always(func() { always = true }).Panic(func(PanicInfo) { panicked = true }).Recover()
+		panicFunc(http.ErrAbortHandler)
+	})
+	assert.False(t, watched || always || panicked)
+
+	errAbort := errors.New("abort")
+	a = Use(Default().SetWatch(func(pi PanicInfo) { watched = true }).AddPassThrough(ErrorIs(errAbort)).SetPassThroughAlways(true))
+	assert.PanicsWithValue(t, errAbort, func() {
+		defer a.Always(func() { always = true }).Succeed(func() { succeed = true }).Recover()
+		panicFunc(errAbort)
+	})
+	assert.False(t, watched || succeed)
+	assert.True(t, always)
+	assert.Panics(t, func() {
+		defer a.Recover()
+		panicFunc(http.ErrAbortHandler)
+	})
+
+	a = Use(Default().SetPassThrough())
+	assert.NotPanics(t, func() {
+		defer a.Recover()
+		panicFunc(http.ErrAbortHandler)
+	})
+}
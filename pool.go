@@ -0,0 +1,161 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPoolClosed is the error of tasks submitted after the pool is closed.
+var ErrPoolClosed = errors.New("panics: pool is closed")
+
+// PoolConfig configures Pool.
+type PoolConfig struct {
+	Workers   int // the number of workers, 1 if less than 1
+	QueueSize int // the number of tasks waiting for workers, Submit blocks if the queue is full
+}
+
+// PoolStats is the statistics of tasks run by Pool.
+type PoolStats struct {
+	Succeeded int64 // tasks returned nil
+	Failed    int64 // tasks returned an error
+	Panicked  int64 // tasks panicked, each of them replaced a worker
+	Goexited  int64 // tasks exited by runtime.Goexit, their error is ErrGoexit and each of them replaced a worker
+}
+
+// Future is the result of a task submitted to Pool.
+type Future struct {
+	done chan struct{}
+	err  error
+}
+
+// Done return a channel which is closed once the task finishes.
+func (f *Future) Done() <-chan struct{} { return f.done }
+
+// Wait wait for the task to finish and return its error, a *PanicError if it panicked. The error of ctx is returned if
+// ctx is done first.
+func (f *Future) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *Future) finish(err error) *Future {
+	f.err = err
+	close(f.done)
+	return f
+}
+
+type poolTask struct {
+	ctx    context.Context
+	f      func(ctx context.Context) error
+	future *Future
+}
+
+// Pool runs tasks with a fixed number of workers. The panics of tasks are recovered and reported through the action the
+// pool is created with, and the worker of a panicked task is replaced by a new one.
+type Pool struct {
+	a     action
+	tasks chan poolTask
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	succeeded, failed, panicked, goexited atomic.Int64
+}
+
+// NewPool create a pool whose tasks are recovered with this action.
+func (a action) NewPool(cfg PoolConfig) *Pool {
+	p := &Pool{a: a, tasks: make(chan poolTask, max(cfg.QueueSize, 0))}
+	for i := 0; i < max(cfg.Workers, 1); i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Submit queue f to run with ctx, it blocks if the queue is full. The future fails with the error of ctx if ctx is
+// done before f runs, or ErrPoolClosed if the pool is closed.
+func (p *Pool) Submit(ctx context.Context, f func(ctx context.Context) error) *Future {
+	future := &Future{done: make(chan struct{})}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return future.finish(ErrPoolClosed)
+	}
+	select {
+	case p.tasks <- poolTask{ctx: ctx, f: f, future: future}:
+		return future
+	case <-ctx.Done():
+		return future.finish(ctx.Err())
+	}
+}
+
+// Close stop accepting tasks and wait for the queued tasks to finish.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// Stats return the statistics of finished tasks.
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		Succeeded: p.succeeded.Load(),
+		Failed:    p.failed.Load(),
+		Panicked:  p.panicked.Load(),
+		Goexited:  p.goexited.Load(),
+	}
+}
+
+func (p *Pool) work() {
+	replace := false
+	defer func() {
+		// replace the worker by defer, so it's replaced even if the task exits the goroutine by runtime.Goexit, and
+		// nothing left by the panicked task affects the following ones
+		if replace {
+			p.wg.Add(1)
+			go p.work()
+		}
+		p.wg.Done()
+	}()
+	for task := range p.tasks {
+		replace = true // kept if exec doesn't return because of runtime.Goexit
+		if replace = p.exec(task); replace {
+			return
+		}
+	}
+}
+
+// exec run the task, it reports whether the task panicked.
+func (p *Pool) exec(task poolTask) (panicked bool) {
+	if err := task.ctx.Err(); err != nil {
+		p.failed.Add(1)
+		task.future.finish(err)
+		return false
+	}
+	p.a.runTask(task.ctx, func() error { return task.f(task.ctx) }, func(err error) {
+		_, panicked = err.(*PanicError)
+		switch {
+		case panicked:
+			p.panicked.Add(1)
+		case err == ErrGoexit:
+			p.goexited.Add(1)
+		case err != nil:
+			p.failed.Add(1)
+		default:
+			p.succeeded.Add(1)
+		}
+		task.future.finish(err)
+	})
+	return panicked
+}
@@ -0,0 +1,82 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	var watched atomic.Int64
+	a := Use(Default().SetWatch(func(info PanicInfo) { watched.Add(1) }))
+	p := a.NewPool(PoolConfig{Workers: 2, QueueSize: 4})
+	errA := errors.New("a")
+
+	var futures []*Future
+	for i := 0; i < 30; i++ {
+		i := i
+		futures = append(futures, p.Submit(context.Background(), func(ctx context.Context) error {
+			switch i % 3 {
+			case 0:
+				panicFunc("a")
+			case 1:
+				return errA
+			}
+			return nil
+		}))
+	}
+	for i, f := range futures {
+		err := f.Wait(context.Background())
+		switch i % 3 {
+		case 0:
+			var panicErr *PanicError
+			if assert.ErrorAs(t, err, &panicErr) {
+				assert.Equal(t, "a", panicErr.Value)
+			}
+		case 1:
+			assert.ErrorIs(t, err, errA)
+		default:
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, PoolStats{Succeeded: 10, Failed: 10, Panicked: 10}, p.Stats())
+	assert.EqualValues(t, 10, watched.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.Submit(ctx, func(ctx context.Context) error { return nil }).Wait(context.Background()), context.Canceled)
+
+	p.Close()
+	f := p.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	<-f.Done()
+	assert.ErrorIs(t, f.Wait(context.Background()), ErrPoolClosed)
+}
+
+func TestPoolGoexit(t *testing.T) {
+	p := Use(Default()).NewPool(PoolConfig{Workers: 1})
+	defer p.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	assert.ErrorIs(t, p.Submit(ctx, func(ctx context.Context) error { runtime.Goexit(); return nil }).Wait(ctx), ErrGoexit)
+	assert.NoError(t, p.Submit(ctx, func(ctx context.Context) error { return nil }).Wait(ctx), "the worker is replaced")
+	assert.Equal(t, PoolStats{Succeeded: 1, Goexited: 1}, p.Stats())
+}
+
+func TestPoolRepanic(t *testing.T) {
+	p := &Pool{a: Use(Default()).Repanic()}
+	future := &Future{done: make(chan struct{})}
+	assert.PanicsWithValue(t, "a", func() {
+		p.exec(poolTask{ctx: context.Background(), f: func(ctx context.Context) error { panicFunc("a"); return nil }, future: future})
+	})
+	var panicErr *PanicError
+	if assert.ErrorAs(t, future.Wait(context.Background()), &panicErr) {
+		assert.Equal(t, "a", panicErr.Value)
+	}
+	assert.Equal(t, PoolStats{Panicked: 1}, p.Stats(), "a re-panicked task is not counted as Goexit")
+}
@@ -0,0 +1,256 @@
+package panics
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const defaultRedactMask = "[REDACTED]"
+
+var (
+	creditCardPattern  = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	bearerTokenPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	emailPattern       = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+	defaultRedactKeys = []string{"password", "passwd", "secret", "token", "authorization", "api_key", "apikey", "cookie"}
+)
+
+// Redactor scrubs secrets from PanicInfo before it is passed to the watch function and the Panic handler. It matches
+// string contents with patterns, and replaces the values of map entries and attributes whose key contains one of the
+// sensitive key names (case-insensitive).
+type Redactor struct {
+	patterns  []*regexp.Regexp
+	keys      []string
+	mask      string
+	stackArgs bool
+}
+
+// NewRedactor return a redactor with built-in rules: credit card numbers (validated with the Luhn check), bearer tokens,
+// emails, and key names like password, secret, token and authorization.
+func NewRedactor() *Redactor {
+	return &Redactor{
+		patterns: []*regexp.Regexp{creditCardPattern, bearerTokenPattern, emailPattern},
+		keys:     defaultRedactKeys,
+		mask:     defaultRedactMask,
+	}
+}
+
+// AddPatterns add patterns to current redactor, every match of them will be replaced with the mask.
+func (r *Redactor) AddPatterns(patterns ...*regexp.Regexp) *Redactor {
+	r.patterns = append(r.patterns[:len(r.patterns):len(r.patterns)], patterns...)
+	return r
+}
+
+// AddKeys add sensitive key names to current redactor. Values of map entries and attributes whose key contains one of
+// the names are replaced with the mask.
+func (r *Redactor) AddKeys(keys ...string) *Redactor {
+	r.keys = append(r.keys[:len(r.keys):len(r.keys)], keys...)
+	return r
+}
+
+// SetMask set the text that replaces redacted contents, default is "[REDACTED]".
+func (r *Redactor) SetMask(mask string) *Redactor { r.mask = mask; return r }
+
+// SetStackArgs controls whether the argument values of function lines in the stack are zeroed out, e.g.
+// `main.foo({0x1400012c000, 0x5})` becomes `main.foo(...)`.
+func (r *Redactor) SetStackArgs(zero bool) *Redactor { r.stackArgs = zero; return r }
+
+// Redact return a copy of info with secrets scrubbed from Error, Extra, Stack, Fields, Attrs, Chain, Goroutines and the
+// raw lines of Direct/Actual and the frames of Explain.
+func (r *Redactor) Redact(info PanicInfo) PanicInfo {
+	info.Error = r.redactValue(info.Error)
+	info.Extra = r.redactValue(info.Extra)
+	info.Stack = r.redactStack(info.Stack)
+	info.Direct = r.redactPosition(info.Direct)
+	info.Actual = r.redactPosition(info.Actual)
+	if info.Fields != nil {
+		fields := make(map[string]any, len(info.Fields))
+		for k, v := range info.Fields {
+			fields[k] = r.redactEntry(k, v)
+		}
+		info.Fields = fields
+	}
+	info.Attrs = r.redactAttrs(info.Attrs)
+	if info.Chain != nil {
+		chain := make([]PanicLink, len(info.Chain))
+		for i, link := range info.Chain {
+			link.Error = r.redactValue(link.Error)
+			link.Direct, link.Actual = r.redactPosition(link.Direct), r.redactPosition(link.Actual)
+			chain[i] = link
+		}
+		info.Chain = chain
+	}
+	if info.Explain != nil {
+		explain := make([]Decision, len(info.Explain))
+		for i, decision := range info.Explain {
+			decision.Frame = r.redactPosition(decision.Frame)
+			explain[i] = decision
+		}
+		info.Explain = explain
+	}
+	if info.Goroutines != nil {
+		goroutines := make([]Goroutine, len(info.Goroutines))
+		for i, g := range info.Goroutines {
+			g.Stack = r.redactStack(g.Stack)
+			frames := make([]Position, len(g.Frames))
+			for j, frame := range g.Frames {
+				frames[j] = r.redactPosition(frame)
+			}
+			g.Frames = frames
+			goroutines[i] = g
+		}
+		info.Goroutines = goroutines
+	}
+	return info
+}
+
+func (r *Redactor) redactString(s string) string {
+	for _, pattern := range r.patterns {
+		if pattern != creditCardPattern {
+			s = pattern.ReplaceAllLiteralString(s, r.mask)
+			continue
+		}
+		// long digit runs like timestamps and IDs are kept unless they pass the Luhn check
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if luhnValid(match) {
+				return r.mask
+			}
+			return match
+		})
+	}
+	return s
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum, spaces and dashes are skipped.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func (r *Redactor) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) redactEntry(key string, v any) any {
+	if r.sensitiveKey(key) {
+		return r.mask
+	}
+	return r.redactValue(v)
+}
+
+func (r *Redactor) redactValue(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.redactString(v)
+	case error:
+		// the original error is dropped on purpose, unwrapping it would expose the secrets again
+		if msg := r.redactString(v.Error()); msg != v.Error() {
+			return errors.New(msg)
+		}
+		return v
+	case fmt.Stringer:
+		if str := r.redactString(v.String()); str != v.String() {
+			return str
+		}
+		return v
+	case map[string]string:
+		m := make(map[string]string, len(v))
+		for k, val := range v {
+			if r.sensitiveKey(k) {
+				m[k] = r.mask
+			} else {
+				m[k] = r.redactString(val)
+			}
+		}
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[k] = r.redactEntry(k, val)
+		}
+		return m
+	case []slog.Attr:
+		return r.redactAttrs(v)
+	default:
+		return v
+	}
+}
+
+func (r *Redactor) redactAttrs(attrs []slog.Attr) []slog.Attr {
+	if len(attrs) == 0 {
+		return attrs
+	}
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		v := attr.Value.Resolve()
+		switch {
+		case r.sensitiveKey(attr.Key):
+			redacted[i] = slog.String(attr.Key, r.mask)
+		case v.Kind() == slog.KindGroup:
+			redacted[i] = slog.Attr{Key: attr.Key, Value: slog.GroupValue(r.redactAttrs(v.Group())...)}
+		case v.Kind() == slog.KindString:
+			redacted[i] = slog.String(attr.Key, r.redactString(v.String()))
+		case v.Kind() == slog.KindAny:
+			redacted[i] = slog.Any(attr.Key, r.redactValue(v.Any()))
+		default:
+			redacted[i] = slog.Attr{Key: attr.Key, Value: v}
+		}
+	}
+	return redacted
+}
+
+func (r *Redactor) redactPosition(p Position) Position {
+	if r.stackArgs {
+		p.FuncLine = zeroStackArgs(p.FuncLine)
+	}
+	p.FuncLine, p.FileLine = r.redactString(p.FuncLine), r.redactString(p.FileLine)
+	return p
+}
+
+func (r *Redactor) redactStack(stack string) string {
+	if r.stackArgs {
+		lines := strings.Split(stack, "\n")
+		for i, line := range lines {
+			lines[i] = zeroStackArgs(line)
+		}
+		stack = strings.Join(lines, "\n")
+	}
+	return r.redactString(stack)
+}
+
+// zeroStackArgs replace the arguments of a function line in the stack with "...", file lines (which are indented) and
+// other lines are returned as they are.
+func zeroStackArgs(line string) string {
+	if line == "" || line[0] == '\t' || line[0] == ' ' || !strings.HasSuffix(line, ")") {
+		return line
+	}
+	idx := strings.LastIndex(line, "(")
+	if idx < 0 || idx == len(line)-2 {
+		return line
+	}
+	return line[:idx] + "(...)"
+}
@@ -0,0 +1,73 @@
+package panics
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor(t *testing.T) {
+	t.Run("BuiltInRules", func(t *testing.T) {
+		r := NewRedactor()
+		info := r.Redact(PanicInfo{
+			Error: errors.New("card 4111 1111 1111 1111 of bob@example.com"),
+			Extra: map[string]any{"Authorization": "Bearer abc.def", "note": "Bearer abc.def"},
+			Attrs: []slog.Attr{slog.String("api_key", "k"), slog.Group("req", slog.String("from", "bob@example.com"))},
+		})
+		assert.EqualError(t, info.Error.(error), "card [REDACTED] of [REDACTED]")
+		assert.Equal(t, map[string]any{"Authorization": "[REDACTED]", "note": "[REDACTED]"}, info.Extra)
+		assert.Equal(t, []slog.Attr{
+			slog.String("api_key", "[REDACTED]"),
+			slog.Group("req", slog.String("from", "[REDACTED]")),
+		}, info.Attrs)
+
+		kept := r.Redact(PanicInfo{Error: errors.New("ts 1760000000000000000 ok, card 4111-1111-1111-1112")})
+		assert.EqualError(t, kept.Error.(error), "ts 1760000000000000000 ok, card 4111-1111-1111-1112", "digits failing the Luhn check are kept")
+	})
+	t.Run("CustomRules", func(t *testing.T) {
+		r := NewRedactor().AddPatterns(regexp.MustCompile(`sid-\d+`)).AddKeys("session").SetMask("***")
+		info := r.Redact(PanicInfo{
+			Error:  "sid-123",
+			Fields: map[string]any{"session_id": 1, "request_id": "r1"},
+		})
+		assert.Equal(t, "***", info.Error)
+		assert.Equal(t, map[string]any{"session_id": "***", "request_id": "r1"}, info.Fields)
+	})
+	t.Run("StackArgs", func(t *testing.T) {
+		stack := "goroutine 1 [running]:\nmain.(*T).foo({0x1400012c000, 0x5})\n\t/a/main.go:12 +0x74\nmain.main()\n\t/a/main.go:3"
+		info := NewRedactor().SetStackArgs(true).Redact(PanicInfo{
+			Stack:  stack,
+			Direct: Position{FuncLine: "main.(*T).foo({0x1400012c000, 0x5})"},
+		})
+		assert.Equal(t, "goroutine 1 [running]:\nmain.(*T).foo(...)\n\t/a/main.go:12 +0x74\nmain.main()\n\t/a/main.go:3", info.Stack)
+		assert.Equal(t, "main.(*T).foo(...)", info.Direct.FuncLine)
+
+		explain := []Decision{{Frame: Position{FuncLine: "main.foo({0x1, 0x2})", FileLine: "\t/a/bob@example.com/main.go:3"}}}
+		info = NewRedactor().SetStackArgs(true).Redact(PanicInfo{Explain: explain})
+		assert.Equal(t, Position{FuncLine: "main.foo(...)", FileLine: "\t/a/[REDACTED]/main.go:3"}, info.Explain[0].Frame)
+		assert.Equal(t, "main.foo({0x1, 0x2})", explain[0].Frame.FuncLine, "the explain of the original info is not modified")
+	})
+	t.Run("Chain", func(t *testing.T) {
+		chain := []PanicLink{{Error: "token bearer abc", Direct: Position{FuncLine: "main.foo({0x1, 0x2})"}}, {Depth: 1}}
+		info := NewRedactor().SetStackArgs(true).Redact(PanicInfo{Chain: chain})
+		assert.Equal(t, "token [REDACTED]", info.Chain[0].Error)
+		assert.Equal(t, "main.foo(...)", info.Chain[0].Direct.FuncLine)
+		assert.Equal(t, 1, info.Chain[1].Depth)
+		assert.Equal(t, "token bearer abc", chain[0].Error, "the chain of the original info is not modified")
+	})
+	t.Run("BeforeWatch", func(t *testing.T) {
+		var watched, handled PanicInfo
+		a := Use(Default().SetWatch(func(pi PanicInfo) { watched = pi }).SetRedactor(NewRedactor()))
+		func() {
+			defer a.Panic(func(pi PanicInfo) { handled = pi }).Recover()
+			panic("token for bob@example.com")
+		}()
+		assert.Equal(t, "token for [REDACTED]", watched.Error)
+		assert.Equal(t, "token for [REDACTED]", handled.Error)
+		assert.False(t, strings.Contains(watched.Stack, "bob@example.com"))
+	})
+}
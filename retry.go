@@ -0,0 +1,79 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy configures Retry.
+type RetryPolicy struct {
+	Attempts   int           // the max number of attempts including the first one, f runs once if it's less than 1
+	Initial    time.Duration // the delay before the second attempt
+	Max        time.Duration // the max delay between attempts, no limit if 0
+	Multiplier float64       // the delay is multiplied by it after every attempt, 2 if 0
+	Jitter     float64       // the fraction of every delay which is randomized, in [0, 1]
+
+	RetryOnError bool             // retry if f returns an error too, not only if f panics
+	RetryIf      func(error) bool // decide whether a returned error is retried if RetryOnError is true, all errors if nil
+}
+
+// delay return the delay after the attempt-th attempt.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	d := float64(p.Initial)
+	for i := 1; i < attempt; i++ {
+		d *= multiplier
+		if p.Max > 0 && d >= float64(p.Max) {
+			break
+		}
+	}
+	if p.Max > 0 && d > float64(p.Max) {
+		d = float64(p.Max)
+	}
+	if p.Jitter > 0 {
+		d -= d * min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return true
+	}
+	return p.RetryOnError && (p.RetryIf == nil || p.RetryIf(err))
+}
+
+// Retry run f under recovery and retry it with exponential backoff if it panics, or returns an error if
+// RetryOnError is set. Every recovered panic is reported with its attempt number in PanicInfo.Attempt. nil is returned
+// once an attempt succeeds, otherwise the errors of all attempts (a *PanicError for a panic) are joined with errors.Join,
+// with the error of ctx if it's done while waiting for the next attempt.
+func (a action) Retry(ctx context.Context, policy RetryPolicy, f func(ctx context.Context) error) error {
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		a.attempt = attempt
+		var err error
+		if panicErr := a.run(ctx, func() { err = f(ctx) }); panicErr != nil {
+			err = panicErr
+		}
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if errors.Is(err, ErrBreakerOpen) || !policy.retryable(err) || attempt >= policy.Attempts {
+			return errors.Join(errs...)
+		}
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(append(errs, ctx.Err())...)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	var attempts []int
+	a := Use(Default().SetWatch(func(info PanicInfo) { attempts = append(attempts, info.Attempt) }))
+	policy := RetryPolicy{Attempts: 3, Initial: time.Millisecond, Jitter: 0.5}
+	errA := errors.New("a")
+
+	t.Run("Succeed", func(t *testing.T) {
+		attempts = nil
+		calls := 0
+		err := a.Retry(context.Background(), policy, func(ctx context.Context) error {
+			if calls++; calls < 3 {
+				panicFunc("a")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []int{1, 2}, attempts)
+	})
+	t.Run("Exhausted", func(t *testing.T) {
+		attempts = nil
+		err := a.Retry(context.Background(), policy, func(ctx context.Context) error { panicFunc("a"); return nil })
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+		var panicErr *PanicError
+		if assert.ErrorAs(t, err, &panicErr) {
+			assert.Equal(t, "a", panicErr.Value)
+		}
+		assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+	})
+	t.Run("Error", func(t *testing.T) {
+		calls := 0
+		err := a.Retry(context.Background(), policy, func(ctx context.Context) error { calls++; return errA })
+		assert.Equal(t, 1, calls, "errors are not retried by default")
+		assert.ErrorIs(t, err, errA)
+
+		calls = 0
+		p := policy
+		p.RetryOnError = true
+		err = a.Retry(context.Background(), p, func(ctx context.Context) error { calls++; return errA })
+		assert.Equal(t, 3, calls)
+		assert.ErrorIs(t, err, errA)
+
+		calls = 0
+		p.RetryIf = func(err error) bool { return !errors.Is(err, errA) }
+		err = a.Retry(context.Background(), p, func(ctx context.Context) error { calls++; return errA })
+		assert.Equal(t, 1, calls)
+		assert.ErrorIs(t, err, errA)
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := policy
+		p.Initial = time.Hour
+		err := a.Retry(ctx, p, func(ctx context.Context) error { cancel(); panicFunc("a"); return nil })
+		assert.ErrorIs(t, err, context.Canceled)
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+	})
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Initial: time.Second, Max: 5 * time.Second}
+	assert.Equal(t, time.Second, p.delay(1))
+	assert.Equal(t, 2*time.Second, p.delay(2))
+	assert.Equal(t, 4*time.Second, p.delay(3))
+	assert.Equal(t, 5*time.Second, p.delay(4))
+	assert.Equal(t, 5*time.Second, p.delay(100))
+
+	p.Multiplier, p.Jitter = 3, 0.5
+	for i := 0; i < 10; i++ {
+		d := p.delay(2)
+		assert.True(t, d > 1500*time.Millisecond && d <= 3*time.Second, d)
+	}
+}
@@ -0,0 +1,125 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"slices"
+)
+
+// exit is the function to terminate the process, it's replaced in tests.
+var exit = os.Exit
+
+// Outcome is what happens to a recovered panic after it's reported.
+type Outcome int
+
+const (
+	OutcomeSwallow  Outcome = iota // the panic is swallowed
+	OutcomeRepanic                 // re-panic with the original value
+	OutcomeExit                    // run the before-exit hook of settings, then terminate the process with Rule.ExitCode
+	OutcomeEscalate                // report the panic to the watch function of the settings named Rule.Escalate too, a missing target is logged
+)
+
+// Rule decides the outcome of the panics it matches. All the non-empty conditions must match, a rule without any
+// condition matches all panics.
+type Rule struct {
+	Alias    string             // glob matched against PanicInfo.Alias, the syntax is the same as path.Match
+	Settings string             // the name of settings passed to ByName
+	Package  string             // glob matched against the package of PanicInfo.Actual
+	Kinds    []Kind             // PanicInfo.Kind must be one of them
+	Match    func(err any) bool // matched against the recovered value before redaction, e.g. ErrorAs[*MyError]()
+
+	Outcome  Outcome
+	ExitCode int    // the exit code for OutcomeExit
+	Escalate string // the name of settings for OutcomeEscalate
+}
+
+// ErrorIs return a matcher for Rule.Match, which reports whether the recovered value is an error matching target
+// with errors.Is.
+func ErrorIs(target error) func(err any) bool {
+	return func(err any) bool {
+		e, ok := err.(error)
+		return ok && errors.Is(e, target)
+	}
+}
+
+// ErrorAs return a matcher for Rule.Match, which reports whether the recovered value is an error that can be found as
+// type T with errors.As.
+func ErrorAs[T error]() func(err any) bool {
+	return func(err any) bool {
+		e, ok := err.(error)
+		var target T
+		return ok && errors.As(e, &target)
+	}
+}
+
+// matches reports whether the rule matches the panic, err is the original recovered value since info may be redacted.
+func (r *Rule) matches(settingsName string, err any, info PanicInfo) bool {
+	if r.Alias != "" {
+		if matched, _ := path.Match(r.Alias, info.Alias); !matched {
+			return false
+		}
+	}
+	if r.Settings != "" && r.Settings != settingsName {
+		return false
+	}
+	if r.Package != "" {
+		if matched, _ := path.Match(r.Package, info.Actual.Package); !matched {
+			return false
+		}
+	}
+	if len(r.Kinds) > 0 && !slices.Contains(r.Kinds, info.Kind) {
+		return false
+	}
+	return r.Match == nil || r.Match(err)
+}
+
+// applyOutcome decide what happens to the recovered panic after it's reported. The repanic setting on action has the
+// highest priority, then the rules of settings, and the repanic policy of settings at last.
+func (a action) applyOutcome(ctx context.Context, panicErr any, infos []PanicInfo, safe bool) {
+	if a.repanic == nil {
+		for i := range a.a.load().rules {
+			rule := &a.a.load().rules[i]
+			if rule.matches(a.a.name(), panicErr, infos[0]) {
+				a.applyRule(ctx, rule, panicErr, infos, safe)
+				return
+			}
+		}
+	}
+	switch a.repanicPolicy() {
+	case RepanicOriginal:
+		panic(panicErr)
+	case RepanicWrappedError:
+		panic(&PanicError{Value: panicErr, Stack: infos[0].Stack, Info: infos[0]})
+	}
+}
+
+func (a action) applyRule(ctx context.Context, rule *Rule, panicErr any, infos []PanicInfo, safe bool) {
+	switch rule.Outcome {
+	case OutcomeRepanic:
+		panic(panicErr)
+	case OutcomeExit:
+		if beforeExit := a.a.load().beforeExit; beforeExit != nil && safe {
+			fallbackSafeRun(ctx, &beforeExit)
+		} else if beforeExit != nil {
+			beforeExit()
+		}
+		exit(rule.ExitCode)
+	case OutcomeEscalate:
+		// the escalation target is not created if it's missing, so a typo won't drop the incident silently
+		s, ok := namedSettings.Load(rule.Escalate)
+		if !ok {
+			logger.Printf("[WATCHER]escalation target %q of rule is not found, the panic is only reported to the current watch functions\n", rule.Escalate)
+			return
+		}
+		watch := s.(*settings).watch
+		for _, info := range infos {
+			if safe {
+				fallbackSafeRunWithInfo(ctx, &watch, info)
+			} else if watch != nil {
+				watch(info)
+			}
+		}
+	}
+}
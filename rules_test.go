@@ -0,0 +1,103 @@
+package panics
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	var exitCode int
+	originExit := exit
+	defer func() { exit = originExit }()
+	exit = func(code int) { exitCode = code }
+
+	var escalated []PanicInfo
+	StoreSettings("TestRules.oncall", Default().SetWatch(func(pi PanicInfo) { escalated = append(escalated, pi) }))
+
+	var flushed bool
+	StoreSettings("TestRules", Default().SetBeforeExit(func() { flushed = true }).SetRules(
+		Rule{Alias: "integrity.*", Outcome: OutcomeExit, ExitCode: 3},
+		Rule{Kinds: []Kind{KindNilMapWrite}, Outcome: OutcomeRepanic},
+		Rule{Match: ErrorAs[*fs.PathError](), Outcome: OutcomeEscalate, Escalate: "TestRules.oncall"},
+		Rule{Settings: "TestRules", Package: panicsPkg, Match: ErrorIs(fs.ErrClosed), Outcome: OutcomeRepanic},
+		Rule{Settings: "other", Outcome: OutcomeRepanic},
+	))
+	a := ByName("TestRules")
+
+	t.Run("Exit", func(t *testing.T) {
+		func() {
+			defer a.Alias("integrity.orders").Recover()
+			panic("a")
+		}()
+		assert.Equal(t, 3, exitCode)
+		assert.True(t, flushed)
+	})
+	t.Run("RepanicByKind", func(t *testing.T) {
+		assert.Panics(t, func() {
+			defer a.Recover()
+			var m map[string]int
+			m["a"] = 1
+		})
+	})
+	t.Run("Escalate", func(t *testing.T) {
+		func() {
+			defer a.Recover()
+			panic(&fs.PathError{Op: "open", Path: "a", Err: fs.ErrNotExist})
+		}()
+		assert.Len(t, escalated, 1)
+	})
+	t.Run("RepanicByPackage", func(t *testing.T) {
+		assert.Panics(t, func() {
+			defer a.Recover()
+			panic(fs.ErrClosed)
+		})
+		assert.NotPanics(t, func() {
+			defer Use(Default().SetRules(Rule{Settings: "TestRules", Outcome: OutcomeRepanic})).Recover()
+			panic(fs.ErrClosed)
+		})
+	})
+	t.Run("ActionFirst", func(t *testing.T) {
+		exitCode = 0
+		assert.Panics(t, func() {
+			defer a.Alias("integrity.orders").Repanic().Recover()
+			panic("a")
+		})
+		assert.Equal(t, 0, exitCode)
+	})
+	t.Run("Redacted", func(t *testing.T) {
+		escalated = nil
+		r := NewRedactor().AddPatterns(regexp.MustCompile(`open \w+`))
+		func() {
+			defer Use(Default().SetRedactor(r).SetRules(a.a.load().rules...)).Recover()
+			panic(&fs.PathError{Op: "open", Path: "a", Err: fs.ErrNotExist})
+		}()
+		assert.Len(t, escalated, 1, "rules match the value before redaction")
+		assert.EqualError(t, escalated[0].Error.(error), "[REDACTED]: file does not exist")
+	})
+	t.Run("EscalateMissing", func(t *testing.T) {
+		var buf bytes.Buffer
+		originLogger := logger
+		defer func() { logger = originLogger }()
+		logger = log.New(&buf, "", 0)
+
+		func() {
+			defer Use(Default().SetWatch(discard).SetRules(Rule{Outcome: OutcomeEscalate, Escalate: "TestRules.missing"})).Recover()
+			panic("a")
+		}()
+		assert.Contains(t, buf.String(), `escalation target "TestRules.missing" of rule is not found`)
+		_, ok := namedSettings.Load("TestRules.missing")
+		assert.False(t, ok, "the missing target is not created")
+	})
+	t.Run("NoMatch", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			defer a.Recover()
+			panic(errors.New("a"))
+		})
+	})
+}
@@ -3,8 +3,13 @@ package panics
 import (
 	"context"
 	"log"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type ignorePositionChecker = func(funcLine, fileLine string) bool
@@ -17,6 +22,9 @@ var (
 
 	logger *log.Logger
 
+	// net/http panics with http.ErrAbortHandler to abort a response deliberately
+	defaultPassThrough = []func(err any) bool{ErrorIs(http.ErrAbortHandler)}
+
 	ignoreStdLibChecker ignorePositionChecker = ignoreContainPath{
 		paths: []string{
 			"/src/bufio/",
@@ -68,6 +76,21 @@ var (
 	Recover func()
 	// RecoverWithContext recover panic with context, the context can be get from PanicInfo.Context.
 	RecoverWithContext func(ctx context.Context)
+	// Go run f in a new goroutine and recover panics from it. f is skipped if the breaker of this action is open.
+	Go func(f func())
+	// Try run f and recover panics from it. A *PanicError is returned if a panic recovered, or ErrBreakerOpen without
+	// running f if the breaker of this action is open.
+	Try func(f func()) error
+	// Retry run f under recovery and retry it with exponential backoff if it panics, or returns an error if
+	// RetryOnError is set. The errors of all attempts are joined and returned if no attempt succeeds.
+	Retry func(ctx context.Context, policy RetryPolicy, f func(ctx context.Context) error) error
+	// Supervise create a supervisor for the children, the panics of children are recovered with the global settings and
+	// the name of child as alias.
+	Supervise func(cfg SupervisorConfig, children ...ChildSpec) *Supervisor
+	// NewPool create a pool whose tasks are recovered with the global settings.
+	NewPool func(cfg PoolConfig) *Pool
+	// RunEvery run f every interval until ctx is done, the panics of f are recovered and it keeps running.
+	RunEvery func(ctx context.Context, interval time.Duration, f func(ctx context.Context) error, opts RunEveryOptions) error
 	// Always the given `f` will always be executed. Use `AlwaysRef` if `f` may change.
 	Always func(f func()) action
 	// AlwaysRef the given `f` will always be executed. Use `Always` if `f` won't change.
@@ -76,10 +99,27 @@ var (
 	Succeed func(f func()) action
 	// SucceedRef the given `f` will be executed if no panic recovered. Use `Succeed` if `f` won't change.
 	SucceedRef func(f *func()) action
+	// Goexit the given `f` will be executed if the goroutine is exiting by runtime.Goexit, it only works with Go/Try.
+	// Use `GoexitRef` if `f` may change.
+	Goexit func(f func()) action
+	// GoexitRef the given `f` will be executed if the goroutine is exiting by runtime.Goexit, it only works with Go/Try.
+	// Use `Goexit` if `f` won't change.
+	GoexitRef func(f *func()) action
 	// Panic the given `f` will be executed if a panic recovered. Use `PanicRef` if `f` may change.
 	Panic func(f func(PanicInfo)) action
 	// PanicRef the given `f` will be executed if a panic recovered. Use `Panic` if `f` won't change.
 	PanicRef func(f *func(PanicInfo)) action
+	// Repanic re-panic with the original value after the watch functions and hooks run.
+	Repanic func() action
+	// RepanicWrapped re-panic with a *PanicError which keeps the original value and stack after the watch functions and
+	// hooks run.
+	RepanicWrapped func() action
+	// NoRepanic swallow the panic after the watch functions and hooks run, even if the repanic policy or rules of
+	// settings would re-panic or exit.
+	NoRepanic func() action
+	// WithBreaker track panics of this recover with the breaker, keyed by alias. Go/Try are short-circuited while the breaker
+	// is open for the alias.
+	WithBreaker func(b *Breaker) action
 	// Alias set alias for this recover. We can get alias in PanicInfo.Alias.
 	Alias func(alias string) action
 	// Safe controls how the user functions are executed. If safe is given true, all user functions passed with Succeed/Panic/Always
@@ -89,6 +129,19 @@ var (
 	Safe func(safe bool) action
 	// WithExtra anything you want to get from panic info.
 	WithExtra func(any) action
+	// With append key/value pairs to the attributes of this recover, the pairs are interpreted the same way as slog.Logger.With.
+	With func(kv ...any) action
+	// WithAttrs append attributes to this recover. Attributes accumulate across calls and can be got from PanicInfo.Attrs.
+	WithAttrs func(attrs ...slog.Attr) action
+	// CaptureGoroutines controls whether stacks of all goroutines are captured if a panic recovered, it overwrites the
+	// GoroutineDump.When of settings. The capture is still size capped and rate limited.
+	CaptureGoroutines func(capture bool) action
+	// Ignore append checkers for this recover only, they are used after the checkers of settings when finding Actual.
+	Ignore func(checkers ...ignorePositionChecker) action
+	// Watch set a watch function for this recover only, it replaces the watch function of settings.
+	Watch func(f func(PanicInfo)) action
+	// AddWatch set a watch function for this recover only, it's called after the watch function of settings.
+	AddWatch func(f func(PanicInfo)) action
 )
 
 func init() {
@@ -99,15 +152,33 @@ func init() {
 	a := globalSettings.s.newAction()
 	Recover = a.Recover
 	RecoverWithContext = a.RecoverWithContext
+	Go = a.Go
+	Try = a.Try
+	Retry = a.Retry
+	Supervise = a.Supervise
+	NewPool = a.NewPool
+	RunEvery = a.RunEvery
 	Always = a.Always
 	AlwaysRef = a.AlwaysRef
 	Succeed = a.Succeed
 	SucceedRef = a.SucceedRef
+	Goexit = a.Goexit
+	GoexitRef = a.GoexitRef
 	Panic = a.Panic
 	PanicRef = a.PanicRef
+	Repanic = a.Repanic
+	RepanicWrapped = a.RepanicWrapped
+	NoRepanic = a.NoRepanic
+	WithBreaker = a.WithBreaker
 	Alias = a.Alias
 	Safe = a.Safe
 	WithExtra = a.WithExtra
+	With = a.With
+	WithAttrs = a.WithAttrs
+	CaptureGoroutines = a.CaptureGoroutines
+	Ignore = a.Ignore
+	Watch = a.Watch
+	AddWatch = a.AddWatch
 }
 
 func IgnoreStdLibChecker() ignorePositionChecker { return ignoreStdLibChecker }
@@ -116,6 +187,25 @@ type settings struct {
 	ignorePositionCheckers []ignorePositionChecker
 	watch                  func(PanicInfo)
 	safe                   bool
+	contextFields          []contextField
+	attrs                  []slog.Attr
+	redactor               *Redactor
+	goroutineDumper        *goroutineDumper
+	sourceReader           *sourceReader
+	explain                bool
+	chain                  bool
+	detectNilPanic         bool
+	repanic                RepanicPolicy
+	rules                  []Rule
+	beforeExit             func()
+	passThrough            []func(err any) bool
+	passThroughAlways      bool
+	crashLoop              *crashLoop
+}
+
+type contextField struct {
+	name    string
+	extract func(ctx context.Context) (any, bool)
 }
 
 // Default return a default settings instance, which will discard panic info and filter standard libraries(it  may have unexpected situations or bad cases)
@@ -124,6 +214,7 @@ func Default() *settings {
 	return &settings{
 		ignorePositionCheckers: []ignorePositionChecker{ignoreStdLibChecker},
 		watch:                  discard,
+		passThrough:            defaultPassThrough,
 	}
 }
 
@@ -132,7 +223,7 @@ func Use(s *settings) action { return s.newAction() }
 
 // ByName create action with settings stored with the given name, default settings will be used if no settings can be found with the name.
 func ByName(name string) action {
-	return action{a: &nameLazySettings{name: name}}
+	return action{a: &nameLazySettings{n: name}}
 }
 
 // StoreSettings store a setting with name, then we can create action with method: ByName("xxx").
@@ -150,6 +241,124 @@ func (s *settings) SetWatch(f func(PanicInfo)) *settings { s.watch = f; return s
 // panic from user functions won't be recovered.
 func (s *settings) SetSafe(safe bool) *settings { s.safe = safe; return s }
 
+// AddContextField register an extractor on current settings. When a panic is recovered, the extractor is called with
+// PanicInfo.Context and the value is stored in PanicInfo.Fields with the given name if it reports ok. Extractors added later
+// overwrite the fields with the same name.
+func (s *settings) AddContextField(name string, f func(ctx context.Context) (any, bool)) *settings {
+	s.contextFields = append(s.contextFields, contextField{name: name, extract: f})
+	return s
+}
+
+// SetAttrs set default attributes of current settings, they are put before the attributes passed to action.With/WithAttrs
+// in PanicInfo.Attrs. An attribute is dropped if the action has one with the same key.
+func (s *settings) SetAttrs(attrs ...slog.Attr) *settings { s.attrs = attrs; return s }
+
+// SetLabels set static labels like component, team or version on current settings, the key/value pairs are interpreted
+// the same way as slog.Logger.With. Labels are kept in the default attributes: a label replaces the default attribute with
+// the same key, and is attached to every PanicInfo produced by actions created with Use or ByName unless the action has
+// an attribute with the same key.
+func (s *settings) SetLabels(kv ...any) *settings {
+	attrs := slices.Clone(s.attrs)
+	for _, label := range slog.Group("", kv...).Value.Group() {
+		if i := indexAttr(attrs, label.Key); i >= 0 {
+			attrs[i] = label
+		} else {
+			attrs = append(attrs, label)
+		}
+	}
+	s.attrs = attrs
+	return s
+}
+
+// SetRedactor set a redactor to current settings, PanicInfo will be scrubbed by it before the watch function and the
+// Panic handler run. Passing nil disables redaction.
+func (s *settings) SetRedactor(r *Redactor) *settings { s.redactor = r; return s }
+
+// SetGoroutineDump enable capturing stacks of all goroutines on current settings. When a recovered panic matches
+// GoroutineDump.When, the stacks are captured, parsed and attached to PanicInfo.Goroutines. Passing nil disables it.
+func (s *settings) SetGoroutineDump(d *GoroutineDump) *settings {
+	if d == nil {
+		s.goroutineDumper = nil
+	} else {
+		s.goroutineDumper = newGoroutineDumper(*d)
+	}
+	return s
+}
+
+// SetSourceContext enable reading source code on current settings. The lines around Direct and Actual positions are
+// attached to PanicInfo.DirectSource and PanicInfo.ActualSource. Passing nil disables it.
+func (s *settings) SetSourceContext(c *SourceContext) *settings {
+	if c == nil {
+		s.sourceReader = nil
+	} else {
+		s.sourceReader = newSourceReader(*c)
+	}
+	return s
+}
+
+// SetExplain enable explain mode on current settings. In explain mode, how every frame is treated when finding Actual
+// (which checker ignored it, or why it's selected) is recorded in PanicInfo.Explain, so ignore rules can be tuned. Explain
+// mode is also enabled for all settings by environment variable PANICS_DEBUG=1, which logs the decisions as well.
+func (s *settings) SetExplain(explain bool) *settings { s.explain = explain; return s }
+
+// SetChain enable chain mode on current settings. By default, if a recover handler panics while handling a panic, the
+// watch function and the Panic handler are called once for every nested panic found in the stack. In chain mode, they
+// are called only once with PanicInfo.Chain holding all nested panics, and Direct/Actual are the ones of the innermost
+// panic, so one incident is counted once.
+func (s *settings) SetChain(chain bool) *settings { s.chain = chain; return s }
+
+// SetDetectNilPanic controls whether panic(nil) is detected by inspecting the stack when recover() returns nil, which
+// happens with GODEBUG=panicnil=1 or modules declaring go versions before 1.21. Detected nil-panics are reported as
+// *runtime.PanicNilError with KindNil instead of running the Succeed hook. It captures the stack even if no panic
+// happens, so only enable it if needed.
+func (s *settings) SetDetectNilPanic(detect bool) *settings { s.detectNilPanic = detect; return s }
+
+// SetRepanic set the repanic policy of current settings, it decides whether a recovered panic is propagated after the
+// watch function and hooks run. Action.Repanic/RepanicWrapped has a higher priority.
+func (s *settings) SetRepanic(p RepanicPolicy) *settings { s.repanic = p; return s }
+
+// SetRules set the rules of current settings. After a recovered panic is reported, the first rule it matches decides
+// whether it's swallowed, re-panicked, escalated to another named settings, or the process exits. The repanic policy of
+// settings applies if no rule matches, and Action.Repanic/RepanicWrapped has a higher priority than rules.
+func (s *settings) SetRules(rules ...Rule) *settings { s.rules = rules; return s }
+
+// SetBeforeExit set a hook which runs before the process exits because of a panic, it's the place to flush
+// asynchronous sinks.
+func (s *settings) SetBeforeExit(f func()) *settings { s.beforeExit = f; return s }
+
+// SetPassThrough set matchers of the panics which must not be swallowed, replacing the default ones (http.ErrAbortHandler).
+// A matched panic is re-panicked with the original value right after it's recovered, the watch function and the
+// Succeed/Panic hooks are not invoked.
+func (s *settings) SetPassThrough(matchers ...func(err any) bool) *settings {
+	s.passThrough = matchers
+	return s
+}
+
+// AddPassThrough add matchers of the panics which must not be swallowed to current settings, e.g. ErrorIs(ErrAbort) or
+// ErrorAs[*MyAbort](). A matched panic is re-panicked with the original value right after it's recovered.
+func (s *settings) AddPassThrough(matchers ...func(err any) bool) *settings {
+	s.passThrough = append(s.passThrough[:len(s.passThrough):len(s.passThrough)], matchers...)
+	return s
+}
+
+// SetPassThroughAlways controls whether the Always hook runs before a pass-through panic is re-panicked.
+func (s *settings) SetPassThroughAlways(always bool) *settings {
+	s.passThroughAlways = always
+	return s
+}
+
+// SetCrashLoopGuard set a crash-loop guard on current settings. If more than CrashLoopGuard.Threshold panics are recovered
+// within CrashLoopGuard.Window, the before-exit hooks run and the process exits with CrashLoopGuard.ExitCode. Passing nil
+// disables it.
+func (s *settings) SetCrashLoopGuard(g *CrashLoopGuard) *settings {
+	if g == nil {
+		s.crashLoop = nil
+	} else {
+		s.crashLoop = newCrashLoop(*g)
+	}
+	return s
+}
+
 // SetIgnorePositionChecker call SetIgnorePositionChecker on current settings. The checkers are used to find **business-related panic location**.
 // e.g. If the we have a bad code: `fmt.Fprintf(nil, "%v", "a")`, if will panic when is executed with stack:
 //
@@ -192,6 +401,63 @@ func SetWatch(f func(PanicInfo)) { globalSettings.s.watch = f }
 // panic from user functions won't be recovered.
 func SetSafe(safe bool) { globalSettings.s.safe = true }
 
+// AddContextField call AddContextField on default settings. When a panic is recovered, the extractor is called with
+// PanicInfo.Context and the value is stored in PanicInfo.Fields with the given name if it reports ok.
+func AddContextField(name string, f func(ctx context.Context) (any, bool)) {
+	globalSettings.s.AddContextField(name, f)
+}
+
+// SetAttrs call SetAttrs on default settings. The attributes are put before the attributes passed to action.With/WithAttrs
+// in PanicInfo.Attrs.
+func SetAttrs(attrs ...slog.Attr) { globalSettings.s.attrs = attrs }
+
+// SetLabels call SetLabels on default settings. Labels are attached to every PanicInfo unless the action has an attribute
+// with the same key.
+func SetLabels(kv ...any) { globalSettings.s.SetLabels(kv...) }
+
+// SetRedactor call SetRedactor on default settings. PanicInfo will be scrubbed by the redactor before the watch function
+// and the Panic handler run.
+func SetRedactor(r *Redactor) { globalSettings.s.redactor = r }
+
+// SetGoroutineDump call SetGoroutineDump on default settings. When a recovered panic matches GoroutineDump.When, the
+// stacks of all goroutines are captured, parsed and attached to PanicInfo.Goroutines.
+func SetGoroutineDump(d *GoroutineDump) { globalSettings.s.SetGoroutineDump(d) }
+
+// SetSourceContext call SetSourceContext on default settings. The lines around Direct and Actual positions are attached
+// to PanicInfo.DirectSource and PanicInfo.ActualSource.
+func SetSourceContext(c *SourceContext) { globalSettings.s.SetSourceContext(c) }
+
+// SetExplain call SetExplain on default settings. In explain mode, how every frame is treated when finding Actual is
+// recorded in PanicInfo.Explain.
+func SetExplain(explain bool) { globalSettings.s.explain = explain }
+
+// SetChain call SetChain on default settings. In chain mode, the watch function and the Panic handler are called only
+// once with PanicInfo.Chain holding all nested panics.
+func SetChain(chain bool) { globalSettings.s.chain = chain }
+
+// SetDetectNilPanic call SetDetectNilPanic on default settings. If it's enabled, panic(nil) is detected by inspecting
+// the stack when recover() returns nil.
+func SetDetectNilPanic(detect bool) { globalSettings.s.detectNilPanic = detect }
+
+// SetRepanic call SetRepanic on default settings. The policy decides whether a recovered panic is propagated after the
+// watch function and hooks run.
+func SetRepanic(p RepanicPolicy) { globalSettings.s.repanic = p }
+
+// SetRules call SetRules on default settings. After a recovered panic is reported, the first rule it matches decides
+// what happens to it.
+func SetRules(rules ...Rule) { globalSettings.s.rules = rules }
+
+// SetBeforeExit call SetBeforeExit on default settings. The hook runs before the process exits because of a panic.
+func SetBeforeExit(f func()) { globalSettings.s.beforeExit = f }
+
+// AddPassThrough call AddPassThrough on default settings. A panic matched by the matchers is re-panicked with the
+// original value right after it's recovered.
+func AddPassThrough(matchers ...func(err any) bool) { globalSettings.s.AddPassThrough(matchers...) }
+
+// SetCrashLoopGuard call SetCrashLoopGuard on default settings. If more than CrashLoopGuard.Threshold panics are
+// recovered within CrashLoopGuard.Window, the process exits.
+func SetCrashLoopGuard(g *CrashLoopGuard) { globalSettings.s.SetCrashLoopGuard(g) }
+
 // SetWatchWithSimpleLog call SetWatch on default settings with simpleLog function.
 func SetWatchWithSimpleLog() { globalSettings.s.watch = SimpleLog }
 
@@ -230,10 +496,27 @@ func SetIgnorePositionChecker(checkers ...ignorePositionChecker) {
 
 // SimpleLog a simple watch function that print log with log.Default()
 func SimpleLog(info PanicInfo) {
+	var details string
+	if len(info.Attrs) > 0 {
+		parts := make([]string, 0, len(info.Attrs))
+		for _, attr := range info.Attrs {
+			parts = append(parts, attr.String())
+		}
+		details = " Attrs:[" + strings.Join(parts, " ") + "]."
+	}
+	if info.Kind != "" {
+		details += " Kind:" + string(info.Kind) + "."
+	}
+	if info.Attempt > 0 {
+		details += " Attempt:" + strconv.Itoa(info.Attempt) + "."
+	}
+	if build := info.Build.String(); build != "" {
+		details += " Build:" + build + "."
+	}
 	if info.Alias != "" {
-		logger.Printf("[WATCHER]panic(%d#%s) with error:%v. Stack:%s\n", info.Actual.Depth, info.Alias, info.Error, info.Stack)
+		logger.Printf("[WATCHER]panic(%d#%s) with error:%v.%s Stack:%s\n", info.Actual.Depth, info.Alias, info.Error, details, info.Stack)
 	} else {
-		logger.Printf("[WATCHER]panic(%d) with error:%v. Stack:%s\n", info.Actual.Depth, info.Error, info.Stack)
+		logger.Printf("[WATCHER]panic(%d) with error:%v.%s Stack:%s\n", info.Actual.Depth, info.Error, details, info.Stack)
 	}
 }
 func discard(info PanicInfo) {}
@@ -253,20 +536,24 @@ func (c ignoreContainPath) shouldIgnore(funcLine, fileLine string) bool {
 
 type settingsContainer interface {
 	load() *settings
+	name() string
 }
 
 type staticSettings struct{ s *settings }
 
 func (a *staticSettings) load() *settings { return a.s }
+func (a *staticSettings) name() string    { return "" }
+
+type nameLazySettings struct{ n string }
 
-type nameLazySettings struct{ name string }
+func (a *nameLazySettings) name() string { return a.n }
 
 func (a *nameLazySettings) load() *settings {
-	s, ok := namedSettings.Load(a.name)
+	s, ok := namedSettings.Load(a.n)
 	var s2 *settings
 	if !ok {
 		s2 = Default()
-		namedSettings.Store(a.name, s2)
+		namedSettings.Store(a.n, s2)
 	} else {
 		s2 = s.(*settings)
 	}
@@ -0,0 +1,123 @@
+package panics
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultSourceLines       = 3
+	defaultSourceMaxFileSize = 1 << 20
+	defaultSourceCacheSize   = 64
+)
+
+// SourceContext configures reading the source code around Direct and Actual positions when a panic is recovered. It is
+// mostly useful in development and staging environments where the source files are available.
+type SourceContext struct {
+	Lines       int                      // lines before and after the failing line, 3 if not positive
+	MaxFileSize int64                    // files larger than it are skipped, 1MB if not positive
+	CacheSize   int                      // the max count of files cached, 64 if not positive
+	Rewrite     func(file string) string // rewrite the file path in the stack, for binaries built on another machine
+}
+
+// SourceSnippet is the source code around a position.
+type SourceSnippet struct {
+	File  string       // the path of the file read, may be rewritten
+	Line  int64        // the failing line
+	Lines []SourceLine // the lines around the failing line
+}
+
+// SourceLine is a line of SourceSnippet.
+type SourceLine struct {
+	Number  int64
+	Text    string
+	Failing bool
+}
+
+// String render the snippet with line numbers, the failing line is marked with ">".
+func (s *SourceSnippet) String() string {
+	if s == nil || len(s.Lines) == 0 {
+		return ""
+	}
+	width := len(strconv.FormatInt(s.Lines[len(s.Lines)-1].Number, 10))
+	var b strings.Builder
+	for _, line := range s.Lines {
+		if line.Failing {
+			b.WriteString("> ")
+		} else {
+			b.WriteString("  ")
+		}
+		number := strconv.FormatInt(line.Number, 10)
+		b.WriteString(strings.Repeat(" ", width-len(number)))
+		b.WriteString(number)
+		b.WriteString(" | ")
+		b.WriteString(line.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+type sourceReader struct {
+	cfg SourceContext
+
+	mu    sync.Mutex
+	files map[string][]string // nil lines mean the file can't be read
+	order []string            // file paths in the order they are cached, for eviction
+}
+
+func newSourceReader(cfg SourceContext) *sourceReader {
+	if cfg.Lines <= 0 {
+		cfg.Lines = defaultSourceLines
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = defaultSourceMaxFileSize
+	}
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = defaultSourceCacheSize
+	}
+	return &sourceReader{cfg: cfg, files: make(map[string][]string)}
+}
+
+func (r *sourceReader) snippet(p Position) *SourceSnippet {
+	if p.Line <= 0 || p.File == "" || p.Depth < 0 {
+		return nil
+	}
+	file := p.File
+	if r.cfg.Rewrite != nil {
+		file = r.cfg.Rewrite(file)
+	}
+	lines := r.load(file)
+	if int64(len(lines)) < p.Line {
+		return nil
+	}
+	from, to := max(p.Line-int64(r.cfg.Lines), 1), min(p.Line+int64(r.cfg.Lines), int64(len(lines)))
+	snippet := &SourceSnippet{File: file, Line: p.Line, Lines: make([]SourceLine, 0, to-from+1)}
+	for n := from; n <= to; n++ {
+		snippet.Lines = append(snippet.Lines, SourceLine{Number: n, Text: lines[n-1], Failing: n == p.Line})
+	}
+	return snippet
+}
+
+func (r *sourceReader) load(file string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lines, ok := r.files[file]; ok {
+		return lines
+	}
+	var lines []string
+	if stat, err := os.Stat(file); err == nil && stat.Mode().IsRegular() && stat.Size() <= r.cfg.MaxFileSize {
+		if content, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n"), "\n")
+		}
+	}
+	if len(r.order) >= r.cfg.CacheSize {
+		delete(r.files, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.files[file] = lines
+	r.order = append(r.order, file)
+	return lines
+}
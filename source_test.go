@@ -0,0 +1,53 @@
+package panics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceContext(t *testing.T) {
+	t.Run("Recover", func(t *testing.T) {
+		var info PanicInfo
+		a := Use(Default().SetWatch(func(pi PanicInfo) { info = pi }).SetSourceContext(&SourceContext{Lines: 1}))
+		func() {
+			defer a.Recover()
+			var m map[string]int
+			m["a"] = 1
+		}()
+		assert.NotNil(t, info.ActualSource)
+		assert.Len(t, info.ActualSource.Lines, 3)
+		assert.Equal(t, info.Actual.Line, info.ActualSource.Line)
+		assert.Equal(t, `			m["a"] = 1`, info.ActualSource.Lines[1].Text)
+		assert.True(t, info.ActualSource.Lines[1].Failing)
+		assert.True(t, strings.Contains(info.ActualSource.String(), `> `))
+	})
+	t.Run("Rewrite", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tpanic(1)\n}\n"), 0o600))
+		r := newSourceReader(SourceContext{Rewrite: func(file string) string {
+			return filepath.Join(dir, strings.TrimPrefix(file, "/build/"))
+		}})
+		snippet := r.snippet(Position{File: "/build/main.go", Line: 4})
+		assert.Equal(t, "  1 | package main\n  2 | \n  3 | func main() {\n> 4 | \tpanic(1)\n  5 | }\n", snippet.String())
+		assert.Nil(t, r.snippet(Position{File: "/build/missing.go", Line: 4}))
+		assert.Nil(t, r.snippet(unknownLoc))
+		assert.Equal(t, "", (&SourceSnippet{}).String())
+	})
+	t.Run("Limits", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"a.go", "b.go"} {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("package a\n"), 0o600))
+		}
+		r := newSourceReader(SourceContext{MaxFileSize: 5, CacheSize: 1})
+		assert.Nil(t, r.snippet(Position{File: filepath.Join(dir, "a.go"), Line: 1}))
+
+		r = newSourceReader(SourceContext{CacheSize: 1})
+		assert.NotNil(t, r.snippet(Position{File: filepath.Join(dir, "a.go"), Line: 1}))
+		assert.NotNil(t, r.snippet(Position{File: filepath.Join(dir, "b.go"), Line: 1}))
+		assert.Len(t, r.files, 1)
+	})
+}
@@ -0,0 +1,191 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrTooManyRestarts is returned by Supervisor.Run if the children are restarted too many times.
+var ErrTooManyRestarts = errors.New("panics: too many restarts")
+
+// RestartPolicy decides whether a child of Supervisor is restarted after it exits.
+type RestartPolicy int
+
+const (
+	RestartPermanent RestartPolicy = iota // always restarted
+	RestartTransient                      // restarted only if it panics, returns an error or calls runtime.Goexit
+	RestartTemporary                      // never restarted
+)
+
+// Strategy decides which children are restarted if a child exits.
+type Strategy int
+
+const (
+	OneForOne Strategy = iota // only the exited child is restarted
+	OneForAll                 // all the other children are stopped, then all children are restarted
+)
+
+// ChildSpec describes a child of Supervisor.
+type ChildSpec struct {
+	Name    string                          // the name of child, which is used as the alias of panics recovered from it
+	Run     func(ctx context.Context) error // the child should return once ctx is done
+	Restart RestartPolicy
+}
+
+// SupervisorEventType is the type of SupervisorEvent.
+type SupervisorEventType string
+
+const (
+	EventChildStarted    SupervisorEventType = "started"    // a child is started
+	EventChildExited     SupervisorEventType = "exited"     // a child exited, Err is a *PanicError if it panicked or ErrGoexit
+	EventChildRestarting SupervisorEventType = "restarting" // a child is going to restart after backoff
+	EventGaveUp          SupervisorEventType = "gave_up"    // the max restart intensity is exceeded, the supervisor stops
+)
+
+// SupervisorEvent is a lifecycle event of Supervisor.
+type SupervisorEvent struct {
+	Type     SupervisorEventType
+	Child    string
+	Err      error // the error the child exited with
+	Restarts int   // the number of restarts within SupervisorConfig.Period
+}
+
+// SupervisorConfig configures Supervisor.
+type SupervisorConfig struct {
+	Strategy    Strategy
+	MaxRestarts int           // the supervisor gives up if more than MaxRestarts restarts happen within Period, no limit if 0
+	Period      time.Duration // the sliding window to count restarts, restarts are counted all the time if 0
+	Backoff     RetryPolicy   // the delay before restarting grows with the restarts in Period, Attempts and RetryOnError are unused
+
+	OnEvent func(SupervisorEvent) // called with lifecycle events from the goroutine running Supervisor.Run
+}
+
+// Supervisor runs children and restarts them if they exit, in the way of Erlang supervisors. The panics of children are
+// recovered and reported through the action the supervisor is created with.
+type Supervisor struct {
+	a        action
+	cfg      SupervisorConfig
+	children []ChildSpec
+}
+
+type childExit struct {
+	index int
+	err   error
+}
+
+// Supervise create a supervisor for the children, the panics of children are recovered with this action and the
+// name of child as alias.
+func (a action) Supervise(cfg SupervisorConfig, children ...ChildSpec) *Supervisor {
+	return &Supervisor{a: a, cfg: cfg, children: children}
+}
+
+// Run start all children and supervise them until ctx is done, or no children are running. nil is returned if ctx is
+// done or all children exit without restarting, otherwise ErrTooManyRestarts is returned joined with the error of the
+// last exited child.
+func (s *Supervisor) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		exits    = make(chan childExit, len(s.children))
+		cancels  = make([]context.CancelFunc, len(s.children))
+		running  = 0
+		restarts panicWindow
+		window   = s.cfg.Period
+	)
+	if window <= 0 {
+		window = math.MaxInt64
+	}
+	start := func(i int) {
+		childCtx, childCancel := context.WithCancel(ctx)
+		cancels[i] = childCancel
+		running++
+		s.emit(SupervisorEvent{Type: EventChildStarted, Child: s.children[i].Name})
+		go s.a.Alias(s.children[i].Name).runTask(childCtx, func() error { return s.children[i].Run(childCtx) }, func(err error) {
+			exits <- childExit{index: i, err: err}
+		})
+	}
+	// stop all children and wait for them to exit
+	stopAll := func() {
+		for i, cancel := range cancels {
+			if cancel != nil {
+				cancel()
+				cancels[i] = nil
+			}
+		}
+		for ; running > 0; running-- {
+			e := <-exits
+			s.emit(SupervisorEvent{Type: EventChildExited, Child: s.children[e.index].Name, Err: e.err})
+		}
+	}
+	defer stopAll()
+
+	for i := range s.children {
+		start(i)
+	}
+	for running > 0 {
+		var e childExit
+		select {
+		case <-ctx.Done():
+			return nil
+		case e = <-exits:
+		}
+		running--
+		child := s.children[e.index]
+		s.emit(SupervisorEvent{Type: EventChildExited, Child: child.Name, Err: e.err})
+		cancels[e.index]()
+		cancels[e.index] = nil
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !s.shouldRestart(child, e.err) {
+			continue
+		}
+
+		n := restarts.add(time.Now(), window)
+		if s.cfg.MaxRestarts > 0 && n > s.cfg.MaxRestarts {
+			s.emit(SupervisorEvent{Type: EventGaveUp, Child: child.Name, Err: e.err, Restarts: n})
+			return errors.Join(ErrTooManyRestarts, e.err)
+		}
+		s.emit(SupervisorEvent{Type: EventChildRestarting, Child: child.Name, Err: e.err, Restarts: n})
+		if s.cfg.Strategy == OneForAll {
+			stopAll()
+		}
+		timer := time.NewTimer(s.cfg.Backoff.delay(n))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		if s.cfg.Strategy == OneForOne {
+			start(e.index)
+			continue
+		}
+		for i := range s.children {
+			if s.children[i].Restart != RestartTemporary {
+				start(i)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Supervisor) shouldRestart(child ChildSpec, err error) bool {
+	switch child.Restart {
+	case RestartPermanent:
+		return true
+	case RestartTransient:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+func (s *Supervisor) emit(event SupervisorEvent) {
+	if s.cfg.OnEvent != nil {
+		s.cfg.OnEvent(event)
+	}
+}
@@ -0,0 +1,129 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupervisor(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		aliases []string
+	)
+	a := Use(Default().SetWatch(func(info PanicInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		aliases = append(aliases, info.Alias)
+	}))
+	eventsOf := func(events *[]string) func(SupervisorEvent) {
+		return func(e SupervisorEvent) { *events = append(*events, e.Child+":"+string(e.Type)) }
+	}
+	errA := errors.New("a")
+
+	t.Run("OneForOne", func(t *testing.T) {
+		aliases = nil
+		var events []string
+		calls := map[string]int{}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := a.Supervise(SupervisorConfig{OnEvent: eventsOf(&events)},
+			ChildSpec{Name: "permanent", Run: func(ctx context.Context) error {
+				if calls["permanent"]++; calls["permanent"] < 3 {
+					panicFunc("a")
+				}
+				cancel()
+				return nil
+			}},
+			ChildSpec{Name: "temporary", Restart: RestartTemporary, Run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return errA
+			}},
+		)
+		assert.NoError(t, s.Run(ctx))
+		assert.Equal(t, 3, calls["permanent"])
+		assert.Equal(t, []string{"permanent", "permanent"}, aliases)
+		assert.Equal(t, []string{
+			"permanent:started", "temporary:started",
+			"permanent:exited", "permanent:restarting", "permanent:started",
+			"permanent:exited", "permanent:restarting", "permanent:started",
+		}, events[:8])
+		// the temporary child exits on cancel, racing with the last exit of the permanent child
+		assert.ElementsMatch(t, []string{"permanent:exited", "temporary:exited"}, events[8:])
+	})
+	t.Run("Transient", func(t *testing.T) {
+		calls := 0
+		s := a.Supervise(SupervisorConfig{}, ChildSpec{Name: "transient", Restart: RestartTransient, Run: func(ctx context.Context) error {
+			if calls++; calls == 1 {
+				return errA
+			}
+			return nil
+		}})
+		assert.NoError(t, s.Run(context.Background()))
+		assert.Equal(t, 2, calls, "transient child is restarted only if it fails")
+	})
+	t.Run("Goexit", func(t *testing.T) {
+		var events []string
+		var calls atomic.Int64
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := a.Supervise(SupervisorConfig{OnEvent: eventsOf(&events)}, ChildSpec{Name: "a", Restart: RestartTransient, Run: func(ctx context.Context) error {
+			if calls.Add(1) == 1 {
+				runtime.Goexit()
+			}
+			<-ctx.Done()
+			return nil
+		}})
+		go func() {
+			for calls.Load() < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+		}()
+		assert.NoError(t, s.Run(ctx))
+		assert.Equal(t, []string{"a:started", "a:exited", "a:restarting", "a:started", "a:exited"}, events)
+	})
+	t.Run("OneForAll", func(t *testing.T) {
+		var events []string
+		calls := 0
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := a.Supervise(SupervisorConfig{Strategy: OneForAll, OnEvent: eventsOf(&events)},
+			ChildSpec{Name: "a", Restart: RestartTransient, Run: func(ctx context.Context) error {
+				if calls++; calls == 1 {
+					panicFunc("a")
+				}
+				cancel()
+				return nil
+			}},
+			ChildSpec{Name: "b", Run: func(ctx context.Context) error { <-ctx.Done(); return nil }},
+			ChildSpec{Name: "c", Restart: RestartTemporary, Run: func(ctx context.Context) error { <-ctx.Done(); return nil }},
+		)
+		assert.NoError(t, s.Run(ctx))
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, []string{"a:started", "b:started", "c:started", "a:exited", "a:restarting"}, events[:5])
+		assert.ElementsMatch(t, []string{"b:exited", "c:exited"}, events[5:7])
+		assert.Equal(t, []string{"a:started", "b:started", "a:exited", "b:exited"}, events[7:])
+	})
+	t.Run("MaxRestarts", func(t *testing.T) {
+		var events []string
+		s := a.Supervise(SupervisorConfig{
+			MaxRestarts: 2,
+			Period:      time.Minute,
+			Backoff:     RetryPolicy{Initial: time.Millisecond},
+			OnEvent:     eventsOf(&events),
+		}, ChildSpec{Name: "a", Run: func(ctx context.Context) error { panicFunc("a"); return nil }})
+		err := s.Run(context.Background())
+		assert.ErrorIs(t, err, ErrTooManyRestarts)
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "a:gave_up", events[len(events)-1])
+		assert.Len(t, events, 3*3)
+	})
+}